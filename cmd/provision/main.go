@@ -0,0 +1,64 @@
+// Command provision creates the accounts described in a csv or yaml file, the results file
+// records the status of each account so an interrupted run can be resumed with the same arguments
+//
+// The signing key is read from the EOS_PRIVATE_KEY environment variable, or from a keystore
+// unlocked with the passphrase in the EOS_KEYSTORE_PASSPHRASE environment variable
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8888", "nodeos url")
+	file := flag.String("file", "", "csv or yaml file with the account specs")
+	results := flag.String("results", "provision-results.json", "results file, used to resume the run")
+	creator := flag.String("creator", "eosio", "account that creates and pays for the accounts")
+	permission := flag.String("permission", "active", "permission of the creator")
+	tokenContract := flag.String("token-contract", "eosio.token", "contract of the initial transfers")
+	accountsPerTrx := flag.Int("accounts-per-trx", 10, "number of accounts created in each transaction")
+	keystorePath := flag.String("keystore", "", "keystore file with the signing keys")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	specs, err := service.LoadAccountSpecs(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	eos, err := service.NewEOSFromUrl(*url)
+	if err != nil {
+		log.Fatalf("failed connecting to: %v, error: %v", *url, err)
+	}
+	if *keystorePath != "" {
+		_, err = eos.UnlockKeystore(*keystorePath, os.Getenv("EOS_KEYSTORE_PASSPHRASE"))
+	} else if key := os.Getenv("EOS_PRIVATE_KEY"); key != "" {
+		_, err = eos.AddKey(key)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	report, err := eos.ProvisionAccounts(specs, &service.ProvisionOpts{
+		Creator:           *creator,
+		CreatorPermission: *permission,
+		TokenContract:     *tokenContract,
+		AccountsPerTrx:    *accountsPerTrx,
+		ResultsFile:       *results,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	failed := report.Count(service.ProvisionStatusFailed)
+	fmt.Printf("created: %v, skipped: %v, failed: %v, results: %v\n",
+		report.Count(service.ProvisionStatusCreated), report.Count(service.ProvisionStatusSkipped), failed, *results)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
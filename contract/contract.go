@@ -22,6 +22,7 @@
 package contract
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -50,7 +51,12 @@ func (m *Contract) NextCallCounter() uint64 {
 
 // ExecActionC Accepts contract name on which to execute the action
 func (m *Contract) ExecActionC(contract, permissionLevel, action, data interface{}) (*service.PushTransactionFullResp, error) {
-	resp, err := m.EOS.SimpleTrx(contract, action, permissionLevel, data)
+	return m.ExecActionCCtx(context.Background(), contract, permissionLevel, action, data)
+}
+
+// ExecActionCCtx Accepts contract name on which to execute the action
+func (m *Contract) ExecActionCCtx(ctx context.Context, contract, permissionLevel, action, data interface{}) (*service.PushTransactionFullResp, error) {
+	resp, err := m.EOS.SimpleTrxCtx(ctx, contract, action, permissionLevel, data)
 	if err != nil {
 		return nil, err
 	}
@@ -62,23 +68,57 @@ func (m *Contract) BuildAction(actionName, permissionLevel, data interface{}) (*
 }
 
 func (m *Contract) ExecAction(permissionLevel, action, data interface{}) (*service.PushTransactionFullResp, error) {
-	return m.ExecActionC(m.ContractName, permissionLevel, action, data)
+	return m.ExecActionCtx(context.Background(), permissionLevel, action, data)
+}
+
+func (m *Contract) ExecActionCtx(ctx context.Context, permissionLevel, action, data interface{}) (*service.PushTransactionFullResp, error) {
+	return m.ExecActionCCtx(ctx, m.ContractName, permissionLevel, action, data)
 }
 
 func (m *Contract) ExecActions(actions ...*eos.Action) (*service.PushTransactionFullResp, error) {
-	resp, err := m.EOS.Trx(actions...)
+	return m.ExecActionsCtx(context.Background(), actions...)
+}
+
+func (m *Contract) ExecActionsCtx(ctx context.Context, actions ...*eos.Action) (*service.PushTransactionFullResp, error) {
+	resp, err := m.EOS.TrxCtx(ctx, actions...)
 	if err != nil {
 		return nil, err
 	}
 	return &service.PushTransactionFullResp{PushTransactionFullResp: resp}, nil
 }
 
+// ExecActionsInBatches pushes the actions in as many transactions as required by the batch options
+func (m *Contract) ExecActionsInBatches(opts *service.BatchOpts, actions ...*eos.Action) (*service.BatchReport, error) {
+	return m.ExecActionsInBatchesCtx(context.Background(), opts, actions...)
+}
+
+func (m *Contract) ExecActionsInBatchesCtx(ctx context.Context, opts *service.BatchOpts, actions ...*eos.Action) (*service.BatchReport, error) {
+	return m.EOS.BatchTrxCtx(ctx, opts, actions...)
+}
+
+// DryRunAction executes the action without committing it, returning the traces, console output and resource estimates
+func (m *Contract) DryRunAction(permissionLevel, action, data interface{}) (*service.DryRunResult, error) {
+	return m.DryRunActionCtx(context.Background(), permissionLevel, action, data)
+}
+
+func (m *Contract) DryRunActionCtx(ctx context.Context, permissionLevel, action, data interface{}) (*service.DryRunResult, error) {
+	act, err := m.BuildAction(action, permissionLevel, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed dry running action, error building action: %v", err)
+	}
+	return m.EOS.DryRunCtx(ctx, act)
+}
+
 func (m *Contract) ProposeAction(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, permissionLevel, actionName, data interface{}) (*service.ProposeResponse, error) {
+	return m.ProposeActionCtx(context.Background(), proposerName, requested, expireIn, permissionLevel, actionName, data)
+}
+
+func (m *Contract) ProposeActionCtx(ctx context.Context, proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, permissionLevel, actionName, data interface{}) (*service.ProposeResponse, error) {
 	action, err := m.EOS.BuildAction(m.ContractName, actionName, permissionLevel, data)
 	if err != nil {
 		return nil, fmt.Errorf("failed proposing multisig action, error building action: %v", err)
 	}
-	return m.EOS.ProposeMultiSig(proposerName, requested, expireIn, action)
+	return m.EOS.ProposeMultiSigCtx(ctx, proposerName, requested, expireIn, action)
 }
 
 func (m *Contract) ExecActionStr(permissionLevel interface{}, action string, actionData interface{}) (string, error) {
@@ -106,6 +146,10 @@ func (m *Contract) ProposeActionStr(proposerName interface{}, requested []eos.Pe
 }
 
 func (m *Contract) GetTableRows(request eos.GetTableRowsRequest, rows interface{}) error {
+	return m.GetTableRowsCtx(context.Background(), request, rows)
+}
+
+func (m *Contract) GetTableRowsCtx(ctx context.Context, request eos.GetTableRowsRequest, rows interface{}) error {
 
 	if request.Code == "" {
 		request.Code = string(m.ContractName)
@@ -116,10 +160,14 @@ func (m *Contract) GetTableRows(request eos.GetTableRowsRequest, rows interface{
 	if request.Limit == 0 {
 		request.Limit = 100
 	}
-	return m.EOS.GetTableRows(request, rows)
+	return m.EOS.GetTableRowsCtx(ctx, request, rows)
 }
 
 func (m *Contract) GetAllTableRows(request eos.GetTableRowsRequest, keyName string, rows interface{}) error {
+	return m.GetAllTableRowsCtx(context.Background(), request, keyName, rows)
+}
+
+func (m *Contract) GetAllTableRowsCtx(ctx context.Context, request eos.GetTableRowsRequest, keyName string, rows interface{}) error {
 
 	if request.Code == "" {
 		request.Code = string(m.ContractName)
@@ -128,18 +176,30 @@ func (m *Contract) GetAllTableRows(request eos.GetTableRowsRequest, keyName stri
 		request.Scope = string(m.ContractName)
 	}
 
-	return m.EOS.GetAllTableRows(request, keyName, rows)
+	return m.EOS.GetAllTableRowsCtx(ctx, request, keyName, rows)
 }
 
 func (m *Contract) GetAllTableRowsAsMap(request eos.GetTableRowsRequest, keyName string) ([]map[string]interface{}, error) {
-	return m.GetAllTableRowsFromAsMap(request, keyName, "", nil)
+	return m.GetAllTableRowsAsMapCtx(context.Background(), request, keyName)
+}
+
+func (m *Contract) GetAllTableRowsAsMapCtx(ctx context.Context, request eos.GetTableRowsRequest, keyName string) ([]map[string]interface{}, error) {
+	return m.GetAllTableRowsFromAsMapCtx(ctx, request, keyName, "", nil)
 }
 
 func (m *Contract) GetAllTableRowsFromAsMap(request eos.GetTableRowsRequest, keyName, start string, getIndexValue service.GetIndexValue) ([]map[string]interface{}, error) {
-	return m.EOS.GetAllTableRowsFromTillAsMap(request, keyName, start, getIndexValue, "")
+	return m.GetAllTableRowsFromAsMapCtx(context.Background(), request, keyName, start, getIndexValue)
+}
+
+func (m *Contract) GetAllTableRowsFromAsMapCtx(ctx context.Context, request eos.GetTableRowsRequest, keyName, start string, getIndexValue service.GetIndexValue) ([]map[string]interface{}, error) {
+	return m.EOS.GetAllTableRowsFromTillAsMapCtx(ctx, request, keyName, start, getIndexValue, "")
 }
 
 func (m *Contract) GetAllTableRowsFromTillAsMap(request eos.GetTableRowsRequest, keyName, start string, getIndexValue service.GetIndexValue, upperBound string) ([]map[string]interface{}, error) {
+	return m.GetAllTableRowsFromTillAsMapCtx(context.Background(), request, keyName, start, getIndexValue, upperBound)
+}
+
+func (m *Contract) GetAllTableRowsFromTillAsMapCtx(ctx context.Context, request eos.GetTableRowsRequest, keyName, start string, getIndexValue service.GetIndexValue, upperBound string) ([]map[string]interface{}, error) {
 
 	if request.Code == "" {
 		request.Code = string(m.ContractName)
@@ -148,12 +208,16 @@ func (m *Contract) GetAllTableRowsFromTillAsMap(request eos.GetTableRowsRequest,
 		request.Scope = string(m.ContractName)
 	}
 
-	return m.EOS.GetAllTableRowsFromTillAsMap(request, keyName, start, getIndexValue, upperBound)
+	return m.EOS.GetAllTableRowsFromTillAsMapCtx(ctx, request, keyName, start, getIndexValue, upperBound)
 }
 
 func (m *Contract) GetAllTableRowsWithScopesAsMap(table, keyName, start string, getIndexValue service.GetIndexValue) ([]map[string]interface{}, error) {
+	return m.GetAllTableRowsWithScopesAsMapCtx(context.Background(), table, keyName, start, getIndexValue)
+}
+
+func (m *Contract) GetAllTableRowsWithScopesAsMapCtx(ctx context.Context, table, keyName, start string, getIndexValue service.GetIndexValue) ([]map[string]interface{}, error) {
 
-	scopes, err := m.GetAllTableScopes(table)
+	scopes, err := m.GetAllTableScopesCtx(ctx, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed getting scopes for table: %v, error: %v", table, err)
 	}
@@ -164,7 +228,7 @@ func (m *Contract) GetAllTableRowsWithScopesAsMap(table, keyName, start string,
 			Scope: scope.Scope,
 		}
 		// fmt.Printf("Getting rows for scope: %v keyName: %v start: %v", scope.Scope, keyName, start)
-		rows, err := m.GetAllTableRowsFromTillAsMap(req, keyName, start, getIndexValue, "")
+		rows, err := m.GetAllTableRowsFromTillAsMapCtx(ctx, req, keyName, start, getIndexValue, "")
 		if err != nil {
 			return nil, fmt.Errorf("failed getting rows for table: %v and scope: %v, error: %v", table, scope.Scope, err)
 		}
@@ -189,6 +253,10 @@ func (m *Contract) DisplayScope(scope *service.TableScope) {
 }
 
 func (m *Contract) GetTableScopes(request eos.GetTableByScopeRequest) (*service.TableScopesResp, error) {
+	return m.GetTableScopesCtx(context.Background(), request)
+}
+
+func (m *Contract) GetTableScopesCtx(ctx context.Context, request eos.GetTableByScopeRequest) (*service.TableScopesResp, error) {
 
 	if request.Code == "" {
 		request.Code = string(m.ContractName)
@@ -197,23 +265,39 @@ func (m *Contract) GetTableScopes(request eos.GetTableByScopeRequest) (*service.
 	if request.Limit == 0 {
 		request.Limit = 100
 	}
-	return m.EOS.GetTableScopes(request)
+	return m.EOS.GetTableScopesCtx(ctx, request)
 }
 
 func (m *Contract) GetAllTableScopes(table string) ([]*service.TableScope, error) {
-	return m.EOS.GetAllTableScopes(string(m.ContractName), table)
+	return m.GetAllTableScopesCtx(context.Background(), table)
+}
+
+func (m *Contract) GetAllTableScopesCtx(ctx context.Context, table string) ([]*service.TableScope, error) {
+	return m.EOS.GetAllTableScopesCtx(ctx, string(m.ContractName), table)
 }
 
 func (m *Contract) IsTableScopeEmpty(scope, table string) (bool, error) {
-	return m.EOS.IsTableScopeEmpty(string(m.ContractName), scope, table)
+	return m.IsTableScopeEmptyCtx(context.Background(), scope, table)
+}
+
+func (m *Contract) IsTableScopeEmptyCtx(ctx context.Context, scope, table string) (bool, error) {
+	return m.EOS.IsTableScopeEmptyCtx(ctx, string(m.ContractName), scope, table)
 }
 
 func (m *Contract) IsTableEmpty(table string) (bool, error) {
-	return m.EOS.IsTableEmpty(string(m.ContractName), table)
+	return m.IsTableEmptyCtx(context.Background(), table)
+}
+
+func (m *Contract) IsTableEmptyCtx(ctx context.Context, table string) (bool, error) {
+	return m.EOS.IsTableEmptyCtx(ctx, string(m.ContractName), table)
 }
 
 func (m *Contract) AreTablesEmpty(tables []string) (bool, error) {
-	return m.EOS.AreTablesEmpty(string(m.ContractName), tables)
+	return m.AreTablesEmptyCtx(context.Background(), tables)
+}
+
+func (m *Contract) AreTablesEmptyCtx(ctx context.Context, tables []string) (bool, error) {
+	return m.EOS.AreTablesEmptyCtx(ctx, string(m.ContractName), tables)
 }
 
 func (m *Contract) GetValueOrContract(value interface{}) interface{} {
@@ -30,6 +30,7 @@ import (
 
 	eos "github.com/sebastianmontero/eos-go"
 	"github.com/sebastianmontero/eos-go-toolbox/dto"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
 	"github.com/sebastianmontero/eos-go-toolbox/service"
 )
 
@@ -303,17 +304,19 @@ func (m *SettingsContract) GetAllSettingsAsMap() ([]map[string]interface{}, erro
 	})
 }
 
+// GetSetting returns the setting, or an ErrSettingNotFound error if it does not exist
 func (m *SettingsContract) GetSetting(key string) (*Setting, error) {
 	setting, err := m.FindSetting(key)
 	if err != nil {
 		return nil, err
 	}
 	if setting == nil {
-		return nil, fmt.Errorf("setting: %v does not exist", key)
+		return nil, fmt.Errorf("%w: %v", eoserr.ErrSettingNotFound, key)
 	}
 	return setting, nil
 }
 
+// FindSetting returns the setting, or nil if it does not exist
 func (m *SettingsContract) FindSetting(key string) (*Setting, error) {
 	settings, err := m.GetSettings()
 	if err != nil {
@@ -365,6 +368,15 @@ func (m *SettingsContract) SetupConfigSettings(owner eos.AccountName, settings i
 	return m.ExecActions(actions...)
 }
 
+// SetupConfigSettingsInBatches sets the settings using as many transactions as required by the batch options
+func (m *SettingsContract) SetupConfigSettingsInBatches(owner eos.AccountName, settings interface{}, opts *service.BatchOpts) (*service.BatchReport, error) {
+	actions, err := m.BuildSetSettingActions(owner, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed building set setting actions: %v, error: %v", settings, err)
+	}
+	return m.ExecActionsInBatches(opts, actions...)
+}
+
 func (m *SettingsContract) SetupConfigSetting(owner eos.AccountName, configSetting map[interface{}]interface{}) error {
 
 	setting, err := GetConfigSetting(configSetting)
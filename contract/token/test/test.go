@@ -39,7 +39,7 @@ func (m *TestUtil) AssertHasNoBalance(tokenContract, account eos.AccountName, sy
 
 	symb, err := util.ToSymbol(symbol)
 	assert.NilError(m.t, err)
-	actualBalance, err := m.tokenContract.GetBalance(account, symb, tokenContract)
+	actualBalance, err := m.tokenContract.FindBalance(account, symb, tokenContract)
 	assert.NilError(m.t, err)
 	assert.Assert(m.t, actualBalance == nil)
 }
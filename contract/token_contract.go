@@ -1,9 +1,8 @@
 package contract
 
 import (
-	"strings"
-
 	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
 	"github.com/sebastianmontero/eos-go-toolbox/service"
 	"github.com/sebastianmontero/eos-go-toolbox/util"
 	"github.com/sebastianmontero/eos-go/token"
@@ -54,7 +53,7 @@ func (m *TokenContract) CreateTokenBase(contract, data interface{}, failIfExists
 	contract = m.getContract(contract)
 	resp, err := m.ExecActionC(contract, contract, "create", data)
 	if err != nil {
-		if failIfExists || !strings.Contains(err.Error(), "token with symbol already exists") {
+		if failIfExists || !eoserr.IsAssertFailure(err, "token with symbol already exists") {
 			return nil, err
 		}
 	}
@@ -114,6 +113,10 @@ func (m *TokenContract) GetBalance(account, symbol, contract interface{}) (*eosc
 	return m.EOS.GetBalance(account, symbol, m.getContract(contract))
 }
 
+func (m *TokenContract) FindBalance(account, symbol, contract interface{}) (*eosc.Asset, error) {
+	return m.EOS.FindBalance(account, symbol, m.getContract(contract))
+}
+
 func (m *TokenContract) GetStat(symbol, contract interface{}) (*eosc.GetCurrencyStatsResp, error) {
 	return m.EOS.GetCurrencyStat(symbol, m.getContract(contract))
 }
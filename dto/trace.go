@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	eosc "github.com/sebastianmontero/eos-go"
+)
+
+// TransactionTrace is the trace returned by nodeos for a processed transaction
+type TransactionTrace struct {
+	ID           string              `json:"id"`
+	BlockNum     uint32              `json:"block_num"`
+	Receipt      *TransactionReceipt `json:"receipt"`
+	Elapsed      int64               `json:"elapsed"`
+	NetUsage     uint64              `json:"net_usage"`
+	Scheduled    bool                `json:"scheduled"`
+	ActionTraces []*ActionTrace      `json:"action_traces"`
+	Except       *Exception          `json:"except"`
+	ErrorCode    *json.Number        `json:"error_code"`
+}
+
+func (m *TransactionTrace) String() string {
+	str, err := json.Marshal(m)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling transaction trace to json, err: %v", err))
+	}
+	return string(str)
+}
+
+// Console returns the console output of all the actions
+func (m *TransactionTrace) Console() string {
+	var console strings.Builder
+	for _, actionTrace := range m.ActionTraces {
+		console.WriteString(actionTrace.Console)
+	}
+	return console.String()
+}
+
+// FindException returns the transaction exception, or the first action exception if the transaction has none
+func (m *TransactionTrace) FindException() *Exception {
+	if m.Except != nil {
+		return m.Except
+	}
+	for _, actionTrace := range m.ActionTraces {
+		if actionTrace.Except != nil {
+			return actionTrace.Except
+		}
+	}
+	return nil
+}
+
+type TransactionReceipt struct {
+	Status        string `json:"status"`
+	CPUUsageUS    uint32 `json:"cpu_usage_us"`
+	NetUsageWords uint32 `json:"net_usage_words"`
+}
+
+type ActionTrace struct {
+	ActionOrdinal        uint32           `json:"action_ordinal"`
+	CreatorActionOrdinal uint32           `json:"creator_action_ordinal"`
+	Receiver             eosc.AccountName `json:"receiver"`
+	Act                  *ActionTraceAct  `json:"act"`
+	ContextFree          bool             `json:"context_free"`
+	Elapsed              int64            `json:"elapsed"`
+	Console              string           `json:"console"`
+	Except               *Exception       `json:"except"`
+	ReturnValueHexData   string           `json:"return_value_hex_data,omitempty"`
+}
+
+type ActionTraceAct struct {
+	Account       eosc.AccountName       `json:"account"`
+	Name          eosc.ActionName        `json:"name"`
+	Authorization []eosc.PermissionLevel `json:"authorization"`
+	Data          json.RawMessage        `json:"data,omitempty"`
+	HexData       string                 `json:"hex_data,omitempty"`
+}
+
+// Exception is the serialized form of the fc::exception reported by nodeos in traces
+type Exception struct {
+	Code    int               `json:"code"`
+	Name    string            `json:"name"`
+	Message string            `json:"message"`
+	Stack   []*ExceptionStack `json:"stack"`
+}
+
+type ExceptionStack struct {
+	Context *ExceptionContext      `json:"context"`
+	Format  string                 `json:"format"`
+	Data    map[string]interface{} `json:"data"`
+}
+
+type ExceptionContext struct {
+	Level  string `json:"level"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Method string `json:"method"`
+}
+
+// Message returns the stack entry message with the format placeholders replaced by the data values
+func (m *ExceptionStack) Message() string {
+	msg := m.Format
+	for key, value := range m.Data {
+		msg = strings.ReplaceAll(msg, fmt.Sprintf("${%v}", key), fmt.Sprintf("%v", value))
+	}
+	return msg
+}
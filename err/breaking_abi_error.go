@@ -0,0 +1,17 @@
+package err
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BreakingABIError is returned by SetContractWithOpts and ProposeSetContractMultiSigWithOpts when the new ABI
+// has breaking changes and they are not allowed
+type BreakingABIError struct {
+	Account string
+	Changes []string
+}
+
+func (c *BreakingABIError) Error() string {
+	return fmt.Sprintf("the new abi of account: %v has breaking changes: %v", c.Account, strings.Join(c.Changes, "; "))
+}
@@ -0,0 +1,323 @@
+package err
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	eosc "github.com/sebastianmontero/eos-go"
+)
+
+// Nodeos error codes, see libraries/chain/include/eosio/chain/exceptions.hpp
+const (
+	ExpiredTxExceptionCode          = 3040005
+	InvalidRefBlockExceptionCode    = 3040007
+	TxDuplicateCode                 = 3040008
+	ActionValidateExceptionCode     = 3050000
+	AccountNameExistsExceptionCode  = 3050001
+	EOSIOAssertMessageExceptionCode = 3050003
+	EOSIOAssertCodeExceptionCode    = 3050004
+	AccountQueryExceptionCode       = 3060002
+	ResourceExhaustedExceptionCode  = 3080000
+	RAMUsageExceededCode            = 3080001
+	TxNetUsageExceededCode          = 3080002
+	TxCPUUsageExceededCode          = 3080004
+	DeadlineExceptionCode           = 3080006
+	LeewayDeadlineExceptionCode     = 3081001
+	UnsatisfiedAuthorizationCode    = 3090003
+	MissingAuthExceptionCode        = 3090004
+	SetExactCodeCode                = 3160008
+)
+
+const (
+	assertionFailureMsg        = "assertion failure with message: "
+	accountNameTakenMsg        = "name is already taken"
+	codeAlreadyRunningMsg      = "contract is already running this version of code"
+	expiredTxMsg               = "expired transaction"
+	duplicateTxMsg             = "duplicate transaction"
+	invalidRefBlockMsg         = "Invalid Reference Block"
+	resourceExhaustedMsgPrefix = "exceeded the current"
+	insufficientRAMMsg         = "insufficient ram"
+	cpuLimitMsg                = "exceeded the current CPU usage limit"
+	netLimitMsg                = "exceeded the current NET usage limit"
+	insufficientCPUMsg         = "has insufficient cpu resources"
+	insufficientNETMsg         = "has insufficient net resources"
+	txTookTooLongMsg           = "Transaction took too long"
+	abiSerializationTimeMsg    = "ABI serialization time has exceeded"
+	unknownKeyMsg              = "unknown key"
+	resourceNotFoundMsg        = "resource not found"
+	blockTraceMissingMsg       = "block trace missing"
+)
+
+var insufficientRAMRegex = regexp.MustCompile(`account ([a-z1-5.]{1,13}) has insufficient ram; needs (\d+) bytes has (\d+) bytes`)
+
+// exhaustedAccountRegex matches the account named by the RAM, CPU and NET exhaustion errors
+var exhaustedAccountRegex = regexp.MustCompile(`account '?([a-z1-5.]{1,13})'? has insufficient (?:ram|cpu resources|net resources)`)
+
+type ChainErrorDetail struct {
+	Message    string `json:"message"`
+	File       string `json:"file"`
+	LineNumber int    `json:"line_number"`
+	Method     string `json:"method"`
+}
+
+// ChainError is an error reported by nodeos, it exposes the nodeos error code, name and what,
+// as well as the stack of details where the assert messages can be found
+type ChainError struct {
+	HTTPCode int                 `json:"http_code"`
+	Message  string              `json:"message"`
+	Code     int                 `json:"code"`
+	Name     string              `json:"name"`
+	What     string              `json:"what"`
+	Details  []*ChainErrorDetail `json:"details"`
+	cause    error
+}
+
+func NewChainError(apiErr *eosc.APIError) *ChainError {
+	details := make([]*ChainErrorDetail, 0, len(apiErr.ErrorStruct.Details))
+	for _, detail := range apiErr.ErrorStruct.Details {
+		details = append(details, &ChainErrorDetail{
+			Message:    detail.Message,
+			File:       detail.File,
+			LineNumber: detail.LineNumber,
+			Method:     detail.Method,
+		})
+	}
+	return &ChainError{
+		HTTPCode: apiErr.Code,
+		Message:  apiErr.Message,
+		Code:     apiErr.ErrorStruct.Code,
+		Name:     apiErr.ErrorStruct.Name,
+		What:     apiErr.ErrorStruct.What,
+		Details:  details,
+		cause:    apiErr,
+	}
+}
+
+// AsChainError finds the first nodeos error in the error chain and returns it as a ChainError
+func AsChainError(e error) (*ChainError, bool) {
+	if e == nil {
+		return nil, false
+	}
+	var chainErr *ChainError
+	if errors.As(e, &chainErr) {
+		return chainErr, true
+	}
+	var apiErrPtr *eosc.APIError
+	if errors.As(e, &apiErrPtr) && apiErrPtr != nil {
+		return NewChainError(apiErrPtr), true
+	}
+	var apiErr eosc.APIError
+	if errors.As(e, &apiErr) {
+		return NewChainError(&apiErr), true
+	}
+	return nil, false
+}
+
+func (c *ChainError) Error() string {
+	msg := fmt.Sprintf("%v: %v", c.Message, c.What)
+	for _, detail := range c.Details {
+		msg = fmt.Sprintf("%v: %v", msg, detail.Message)
+	}
+	return msg
+}
+
+func (c *ChainError) Unwrap() error {
+	return c.cause
+}
+
+// AssertMessage returns the message of the eosio_assert that failed, empty if the error
+// is not an assert failure
+func (c *ChainError) AssertMessage() string {
+	for _, detail := range c.Details {
+		if pos := strings.Index(detail.Message, assertionFailureMsg); pos != -1 {
+			return detail.Message[pos+len(assertionFailureMsg):]
+		}
+	}
+	return ""
+}
+
+// HasMessage returns true if the what or any of the detail messages contain msg
+func (c *ChainError) HasMessage(msg string) bool {
+	if strings.Contains(c.What, msg) {
+		return true
+	}
+	for _, detail := range c.Details {
+		if strings.Contains(detail.Message, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ChainError) IsAssertFailure() bool {
+	return c.Code == EOSIOAssertMessageExceptionCode ||
+		c.Code == EOSIOAssertCodeExceptionCode ||
+		c.Code == ActionValidateExceptionCode
+}
+
+func (c *ChainError) IsResourceExhausted() bool {
+	category := c.Code / 1000
+	return category == ResourceExhaustedExceptionCode/1000 || category == LeewayDeadlineExceptionCode/1000
+}
+
+// IsAccountExists returns true if the error was caused by trying to create an account that already exists
+func IsAccountExists(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == AccountNameExistsExceptionCode || c.HasMessage(accountNameTakenMsg)
+	}, accountNameTakenMsg)
+}
+
+// IsAssertFailure returns true if the error was caused by an eosio_assert or an action validation
+// that failed with a message that contains msg, an empty msg matches any assert failure
+func IsAssertFailure(e error, msg string) bool {
+	if e == nil {
+		return false
+	}
+	if chainErr, ok := AsChainError(e); ok {
+		return chainErr.IsAssertFailure() && chainErr.HasMessage(msg)
+	}
+	if msg == "" {
+		return strings.Contains(e.Error(), assertionFailureMsg)
+	}
+	return strings.Contains(e.Error(), msg)
+}
+
+// IsExpired returns true if the transaction was rejected because it expired
+func IsExpired(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == ExpiredTxExceptionCode
+	}, expiredTxMsg)
+}
+
+// IsInvalidRefBlock returns true if the transaction was rejected because its TAPOS reference block
+// is not part of the chain
+func IsInvalidRefBlock(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == InvalidRefBlockExceptionCode
+	}, invalidRefBlockMsg)
+}
+
+// IsDuplicate returns true if the transaction was rejected because it had already been pushed
+func IsDuplicate(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == TxDuplicateCode
+	}, duplicateTxMsg)
+}
+
+// IsResourceExhausted returns true if the transaction failed due to lack of RAM, CPU or NET
+func IsResourceExhausted(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.IsResourceExhausted()
+	}, resourceExhaustedMsgPrefix, insufficientRAMMsg, insufficientCPUMsg, insufficientNETMsg)
+}
+
+// IsRAMExhausted returns true if the transaction failed because an account does not have enough RAM
+func IsRAMExhausted(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == RAMUsageExceededCode
+	}, insufficientRAMMsg)
+}
+
+// IsCPUExhausted returns true if the transaction failed because the payer does not have enough CPU
+func IsCPUExhausted(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == TxCPUUsageExceededCode || c.HasMessage(cpuLimitMsg)
+	}, cpuLimitMsg, insufficientCPUMsg)
+}
+
+// IsNETExhausted returns true if the transaction failed because the payer does not have enough NET
+func IsNETExhausted(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == TxNetUsageExceededCode || c.HasMessage(netLimitMsg)
+	}, netLimitMsg, insufficientNETMsg)
+}
+
+// InsufficientRAM returns the account that ran out of RAM and the number of bytes it is missing,
+// ok is false if the error is not an insufficient RAM error
+func InsufficientRAM(e error) (account eosc.AccountName, missingBytes uint64, ok bool) {
+	if e == nil {
+		return "", 0, false
+	}
+	matches := insufficientRAMRegex.FindStringSubmatch(e.Error())
+	if matches == nil {
+		return "", 0, false
+	}
+	needs, err := strconv.ParseUint(matches[2], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	has, err := strconv.ParseUint(matches[3], 10, 64)
+	if err != nil || has > needs {
+		return "", 0, false
+	}
+	return eosc.AccountName(matches[1]), needs - has, true
+}
+
+// ResourceExhaustedAccount returns the account a RAM, CPU or NET exhaustion error names as the one that
+// ran out of the resource, ok is false if the error does not name one
+func ResourceExhaustedAccount(e error) (account eosc.AccountName, ok bool) {
+	if e == nil {
+		return "", false
+	}
+	matches := exhaustedAccountRegex.FindStringSubmatch(e.Error())
+	if matches == nil {
+		return "", false
+	}
+	return eosc.AccountName(matches[1]), true
+}
+
+// IsTransient returns true if nodeos rejected the transaction due to a condition that can clear up
+// on its own, such as the transaction running out of time or CPU, or its reference block being forked out
+func IsTransient(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		switch c.Code {
+		case DeadlineExceptionCode, LeewayDeadlineExceptionCode, TxCPUUsageExceededCode, InvalidRefBlockExceptionCode:
+			return true
+		}
+		return c.HasMessage(abiSerializationTimeMsg)
+	}, txTookTooLongMsg, cpuLimitMsg, abiSerializationTimeMsg, invalidRefBlockMsg)
+}
+
+// IsAccountNotFound returns true if the error was returned by get_account because the account does not exist
+func IsAccountNotFound(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == AccountQueryExceptionCode ||
+			c.HasMessage(unknownKeyMsg) ||
+			strings.Contains(c.Message, resourceNotFoundMsg)
+	}, unknownKeyMsg, resourceNotFoundMsg)
+}
+
+// IsBlockTraceMissing returns true if the error was returned by the trace api because it has no trace for the block
+func IsBlockTraceMissing(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.HTTPCode == 404 && (strings.Contains(c.Message, blockTraceMissingMsg) || c.HasMessage(blockTraceMissingMsg))
+	}, blockTraceMissingMsg)
+}
+
+// IsCodeUnchanged returns true if setcode failed because the account is already running the same code
+func IsCodeUnchanged(e error) bool {
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == SetExactCodeCode
+	}, codeAlreadyRunningMsg)
+}
+
+// isChainError evaluates the matcher if there is a nodeos error in the chain, otherwise it falls back
+// to look for the messages in the error string, to support errors that were not wrapped with %w
+func isChainError(e error, matcher func(c *ChainError) bool, msgs ...string) bool {
+	if e == nil {
+		return false
+	}
+	if chainErr, ok := AsChainError(e); ok {
+		return matcher(chainErr)
+	}
+	errMsg := e.Error()
+	for _, msg := range msgs {
+		if strings.Contains(errMsg, msg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package err_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"gotest.tools/assert"
+)
+
+const assertFailureResp = `{
+	"code": 500,
+	"message": "Internal Service Error",
+	"error": {
+		"code": 3050003,
+		"name": "eosio_assert_message_exception",
+		"what": "eosio_assert_message assertion failure",
+		"details": [{
+			"message": "assertion failure with message: token with symbol already exists",
+			"file": "cf_system.cpp",
+			"line_number": 14,
+			"method": "eosio_assert"
+		}]
+	}
+}`
+
+func TestChainErrorAssertFailure(t *testing.T) {
+	var apiErr eosc.APIError
+	assert.NilError(t, json.Unmarshal([]byte(assertFailureResp), &apiErr))
+	wrapped := fmt.Errorf("failed creating token: %w", apiErr)
+
+	chainErr, ok := eoserr.AsChainError(wrapped)
+	assert.Assert(t, ok)
+	assert.Equal(t, chainErr.Code, eoserr.EOSIOAssertMessageExceptionCode)
+	assert.Equal(t, chainErr.Name, "eosio_assert_message_exception")
+	assert.Equal(t, chainErr.AssertMessage(), "token with symbol already exists")
+	assert.Equal(t, len(chainErr.Details), 1)
+	assert.Equal(t, chainErr.Details[0].Method, "eosio_assert")
+
+	assert.Assert(t, eoserr.IsAssertFailure(wrapped, "token with symbol already exists"))
+	assert.Assert(t, eoserr.IsAssertFailure(wrapped, ""))
+	assert.Assert(t, !eoserr.IsAssertFailure(wrapped, "overdrawn balance"))
+	assert.Assert(t, !eoserr.IsAccountExists(wrapped))
+	assert.Assert(t, !eoserr.IsResourceExhausted(wrapped))
+}
+
+func TestChainErrorFallsBackToMessage(t *testing.T) {
+	e := errors.New("push transaction: Cannot create account named usera, as that name is already taken")
+	_, ok := eoserr.AsChainError(e)
+	assert.Assert(t, !ok)
+	assert.Assert(t, eoserr.IsAccountExists(e))
+	assert.Assert(t, eoserr.IsResourceExhausted(errors.New("billed CPU time (500 us) is greater than the maximum billable CPU time for the transaction; exceeded the current CPU usage limit imposed on the transaction")))
+	assert.Assert(t, !eoserr.IsExpired(nil))
+}
+
+func TestInsufficientRAM(t *testing.T) {
+	e := errors.New("push transaction: account usera has insufficient ram; needs 3028 bytes has 2996 bytes")
+	assert.Assert(t, eoserr.IsRAMExhausted(e))
+	assert.Assert(t, !eoserr.IsCPUExhausted(e))
+	account, missing, ok := eoserr.InsufficientRAM(e)
+	assert.Assert(t, ok)
+	assert.Equal(t, account, eosc.AccountName("usera"))
+	assert.Equal(t, missing, uint64(32))
+	_, _, ok = eoserr.InsufficientRAM(errors.New("overdrawn balance"))
+	assert.Assert(t, !ok)
+}
+
+func TestResourceExhaustedAccount(t *testing.T) {
+	account, ok := eoserr.ResourceExhaustedAccount(errors.New("push transaction: account dao.tokens has insufficient ram; needs 3028 bytes has 2996 bytes"))
+	assert.Assert(t, ok)
+	assert.Equal(t, account, eosc.AccountName("dao.tokens"))
+	cpuErr := errors.New("push transaction: authorizing account 'userb' has insufficient cpu resources for this transaction")
+	assert.Assert(t, eoserr.IsCPUExhausted(cpuErr))
+	account, ok = eoserr.ResourceExhaustedAccount(cpuErr)
+	assert.Assert(t, ok)
+	assert.Equal(t, account, eosc.AccountName("userb"))
+	_, ok = eoserr.ResourceExhaustedAccount(errors.New("billed CPU time (500 us) is greater than the maximum billable CPU time for the transaction"))
+	assert.Assert(t, !ok)
+}
+
+func TestUnsatisfiedAuth(t *testing.T) {
+	e := fmt.Errorf("failed signing, error: %w", &eoserr.UnsatisfiedAuthError{Permissions: []string{"usera@active", "userb@owner"}})
+	assert.Assert(t, eoserr.IsUnsatisfiedAuth(e))
+	assert.ErrorContains(t, e, "usera@active, userb@owner")
+	assert.Assert(t, eoserr.IsUnsatisfiedAuth(errors.New("transaction declares authority '{\"actor\":\"usera\",\"permission\":\"active\"}', but does not have signatures for it.")))
+	assert.Assert(t, !eoserr.IsUnsatisfiedAuth(errors.New("overdrawn balance")))
+}
+
+func chainError(t *testing.T, httpCode, code int, name, what, message string) error {
+	var apiErr eosc.APIError
+	resp := fmt.Sprintf(`{"code": %v, "message": "Internal Service Error", "error": {"code": %v, "name": %q, "what": %q, "details": [{"message": %q}]}}`,
+		httpCode, code, name, what, message)
+	assert.NilError(t, json.Unmarshal([]byte(resp), &apiErr))
+	return fmt.Errorf("push transaction: %w", apiErr)
+}
+
+func TestChainErrorCodes(t *testing.T) {
+	cpu := chainError(t, 500, eoserr.TxCPUUsageExceededCode, "tx_cpu_usage_exceeded", "Transaction exceeded the current CPU usage limit imposed on the transaction", "billed CPU time")
+	assert.Assert(t, eoserr.IsTransient(cpu))
+	assert.Assert(t, eoserr.IsCPUExhausted(cpu))
+	refBlock := chainError(t, 500, eoserr.InvalidRefBlockExceptionCode, "invalid_ref_block_exception", "Invalid Reference Block", "")
+	assert.Assert(t, eoserr.IsTransient(refBlock))
+	assertFailure := chainError(t, 500, eoserr.EOSIOAssertMessageExceptionCode, "eosio_assert_message_exception", "eosio_assert_message assertion failure", "assertion failure with message: exceeded the current CPU usage limit")
+	assert.Assert(t, !eoserr.IsTransient(assertFailure))
+	accountQuery := chainError(t, 500, eoserr.AccountQueryExceptionCode, "account_query_exception", "Account Query Exception", "Fail to retrieve account for usera")
+	assert.Assert(t, eoserr.IsAccountNotFound(accountQuery))
+	assert.Assert(t, !eoserr.IsAccountNotFound(cpu))
+	assert.Assert(t, eoserr.IsBlockTraceMissing(chainError(t, 404, 0, "", "", "Trace API: block trace missing")))
+	assert.Assert(t, !eoserr.IsBlockTraceMissing(cpu))
+}
@@ -0,0 +1,18 @@
+package err
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingSignerError is returned by the routing signer when some authorizations of a transaction
+// are not routed to a signer, or some required key is not held by any signer, in which case the
+// authorizations whose signer holds none of the required keys are reported, Authorizations are in
+// actor@permission format
+type MissingSignerError struct {
+	Authorizations []string
+}
+
+func (c *MissingSignerError) Error() string {
+	return fmt.Sprintf("there is no signer for the authorizations: %v", strings.Join(c.Authorizations, ", "))
+}
@@ -0,0 +1,11 @@
+package err
+
+import "errors"
+
+// Not found errors returned by the Get* functions, the Find* variants return nil instead
+var (
+	ErrAccountNotFound    = errors.New("account not found")
+	ErrPermissionNotFound = errors.New("permission not found")
+	ErrBalanceNotFound    = errors.New("balance not found")
+	ErrSettingNotFound    = errors.New("setting not found")
+)
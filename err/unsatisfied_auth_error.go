@@ -0,0 +1,29 @@
+package err
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UnsatisfiedAuthError is returned when the keys available to the signer can not satisfy
+// some of the authorizations of a transaction, Permissions are in actor@permission format
+type UnsatisfiedAuthError struct {
+	Permissions []string
+}
+
+func (c *UnsatisfiedAuthError) Error() string {
+	return fmt.Sprintf("the available keys can not satisfy the permissions: %v", strings.Join(c.Permissions, ", "))
+}
+
+// IsUnsatisfiedAuth returns true if the error was caused by missing signatures, either detected
+// locally or reported by nodeos
+func IsUnsatisfiedAuth(e error) bool {
+	var unsatisfiedErr *UnsatisfiedAuthError
+	if errors.As(e, &unsatisfiedErr) {
+		return true
+	}
+	return isChainError(e, func(c *ChainError) bool {
+		return c.Code == UnsatisfiedAuthorizationCode
+	}, "but does not have signatures for it")
+}
@@ -5,6 +5,8 @@ go 1.16
 require (
 	github.com/digital-scarcity/eos-go-test v0.0.0-20230415144134-50e76c085618
 	github.com/sebastianmontero/eos-go v0.10.5-0.20251014033848-1f05f693154c
+	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
+	gopkg.in/yaml.v2 v2.2.2
 	gotest.tools v2.2.0+incompatible
 )
 
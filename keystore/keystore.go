@@ -0,0 +1,438 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"golang.org/x/crypto/scrypt"
+)
+
+const Version = 1
+
+const (
+	kdfName        = "scrypt"
+	defaultScryptN = 1 << 15
+	defaultScryptR = 8
+	defaultScryptP = 1
+	keyLen         = 32
+	saltLen        = 32
+	checkValue     = "eos-go-toolbox keystore"
+)
+
+var ErrInvalidPassphrase = errors.New("invalid keystore passphrase")
+var ErrKeyNotFound = errors.New("key not found in keystore")
+var ErrLocked = errors.New("keystore is locked")
+
+type KDFParams struct {
+	Name string `json:"name"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+type EncryptedData struct {
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+type EncryptedKey struct {
+	PublicKey string `json:"public_key"`
+	EncryptedData
+}
+
+// File is the content of a keystore file, private keys are encrypted with AES-256-GCM
+// using a key derived from the passphrase with scrypt, the public key is used as additional
+// data so that encrypted keys can not be swapped
+type File struct {
+	Version int        `json:"version"`
+	KDF     *KDFParams `json:"kdf"`
+	// Check is a known value encrypted with the passphrase, it is used to verify the passphrase
+	Check *EncryptedData  `json:"check"`
+	Keys  []*EncryptedKey `json:"keys"`
+}
+
+// Keystore is an unlocked keystore, it keeps the key derived from the passphrase but not the
+// private keys, which are decrypted only when required to sign
+type Keystore struct {
+	path  string
+	file  *File
+	aead  cipher.AEAD
+	mutex sync.RWMutex
+}
+
+// Create creates a new empty keystore file protected by the passphrase
+func Create(path, passphrase string) (*Keystore, error) {
+	kdf, err := newKDFParams()
+	if err != nil {
+		return nil, err
+	}
+	file := &File{
+		Version: Version,
+		KDF:     kdf,
+		Keys:    make([]*EncryptedKey, 0),
+	}
+	aead, err := deriveAEAD(file.KDF, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	file.Check, err = encrypt(aead, []byte(checkValue), []byte(checkValue))
+	if err != nil {
+		return nil, err
+	}
+	keystore := &Keystore{
+		path: path,
+		file: file,
+		aead: aead,
+	}
+	if err := keystore.save(); err != nil {
+		return nil, err
+	}
+	return keystore, nil
+}
+
+// Open loads the keystore file and unlocks it with the passphrase
+func Open(path, passphrase string) (*Keystore, error) {
+	file, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return file.Unlock(path, passphrase)
+}
+
+// Load reads a keystore file without unlocking it
+func Load(path string) (*File, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading keystore: %v, error: %v", path, err)
+	}
+	file := &File{}
+	err = json.Unmarshal(content, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling keystore: %v, error: %v", path, err)
+	}
+	if file.Version != Version {
+		return nil, fmt.Errorf("unsupported keystore version: %v", file.Version)
+	}
+	if file.KDF == nil || file.Check == nil {
+		return nil, fmt.Errorf("invalid keystore: %v, kdf params or check are missing", path)
+	}
+	return file, nil
+}
+
+// Unlock derives the encryption key from the passphrase and verifies it
+func (m *File) Unlock(path, passphrase string) (*Keystore, error) {
+	aead, err := deriveAEAD(m.KDF, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decrypt(aead, m.Check, []byte(checkValue)); err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	return &Keystore{
+		path: path,
+		file: m,
+		aead: aead,
+	}, nil
+}
+
+// Lock discards the key derived from the passphrase, the keystore has to be opened again to be used
+func (m *Keystore) Lock() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.aead = nil
+}
+
+// IsLocked returns true if Lock has been called
+func (m *Keystore) IsLocked() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.aead == nil
+}
+
+// PublicKeys returns the public keys of the keys in the keystore
+func (m *Keystore) PublicKeys() []ecc.PublicKey {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	keys := make([]ecc.PublicKey, 0, len(m.file.Keys))
+	for _, key := range m.file.Keys {
+		// public keys are validated when added
+		publicKey, _ := ecc.NewPublicKey(key.PublicKey)
+		keys = append(keys, publicKey)
+	}
+	return keys
+}
+
+// Add encrypts the private key and stores it in the keystore, returns its public key
+func (m *Keystore) Add(wifPrivKey string) (*ecc.PublicKey, error) {
+	privateKey, err := ecc.NewPrivateKey(wifPrivKey)
+	if err != nil {
+		// the error of the parser is not included as it could contain the key
+		return nil, fmt.Errorf("failed adding key to keystore, invalid private key")
+	}
+	return m.addKey(privateKey)
+}
+
+// Generate creates a new random key and stores it in the keystore, returns its public key
+func (m *Keystore) Generate() (*ecc.PublicKey, error) {
+	privateKey, err := ecc.NewRandomPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed generating key, error: %v", err)
+	}
+	return m.addKey(privateKey)
+}
+
+func (m *Keystore) addKey(privateKey *ecc.PrivateKey) (*ecc.PublicKey, error) {
+	publicKey := privateKey.PublicKey()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.aead == nil {
+		return nil, ErrLocked
+	}
+	if m.find(publicKey.String()) != nil {
+		return &publicKey, nil
+	}
+	key, err := m.encryptKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	keys := m.file.Keys
+	m.file.Keys = append(keys, key)
+	if err := m.save(); err != nil {
+		m.file.Keys = keys
+		return nil, err
+	}
+	return &publicKey, nil
+}
+
+// Remove deletes the key from the keystore
+func (m *Keystore) Remove(publicKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.aead == nil {
+		return ErrLocked
+	}
+	keys := m.file.Keys
+	m.file.Keys = removeKey(keys, publicKey)
+	if len(m.file.Keys) == len(keys) {
+		return fmt.Errorf("%w: %v", ErrKeyNotFound, publicKey)
+	}
+	if err := m.save(); err != nil {
+		m.file.Keys = keys
+		return err
+	}
+	return nil
+}
+
+// Rotate adds a new random key to replace the key with the specified public key, returns the public key
+// of the new key, the old key is kept so that the permissions that use it can still be updated on chain
+// to use the new one, Remove the old key once the update is confirmed
+func (m *Keystore) Rotate(publicKey string) (*ecc.PublicKey, error) {
+	privateKey, err := ecc.NewRandomPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed generating key, error: %v", err)
+	}
+	newPublicKey := privateKey.PublicKey()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.aead == nil {
+		return nil, ErrLocked
+	}
+	if m.find(publicKey) == nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyNotFound, publicKey)
+	}
+	key, err := m.encryptKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	keys := m.file.Keys
+	m.file.Keys = append(keys, key)
+	if err := m.save(); err != nil {
+		m.file.Keys = keys
+		return nil, err
+	}
+	return &newPublicKey, nil
+}
+
+// ChangePassphrase re-encrypts all the keys with a key derived from the new passphrase and a new salt
+func (m *Keystore) ChangePassphrase(newPassphrase string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.aead == nil {
+		return ErrLocked
+	}
+	kdf, err := newKDFParams()
+	if err != nil {
+		return err
+	}
+	aead, err := deriveAEAD(kdf, newPassphrase)
+	if err != nil {
+		return err
+	}
+	check, err := encrypt(aead, []byte(checkValue), []byte(checkValue))
+	if err != nil {
+		return err
+	}
+	keys := make([]*EncryptedKey, 0, len(m.file.Keys))
+	for _, key := range m.file.Keys {
+		privateKey, err := m.privateKey(key.PublicKey)
+		if err != nil {
+			return err
+		}
+		data, err := encrypt(aead, []byte(privateKey.String()), []byte(key.PublicKey))
+		if err != nil {
+			return err
+		}
+		keys = append(keys, &EncryptedKey{
+			PublicKey:     key.PublicKey,
+			EncryptedData: *data,
+		})
+	}
+	file := m.file
+	m.file = &File{
+		Version: Version,
+		KDF:     kdf,
+		Check:   check,
+		Keys:    keys,
+	}
+	if err := m.save(); err != nil {
+		m.file = file
+		return err
+	}
+	m.aead = aead
+	return nil
+}
+
+func (m *Keystore) encryptKey(privateKey *ecc.PrivateKey) (*EncryptedKey, error) {
+	publicKey := privateKey.PublicKey().String()
+	data, err := encrypt(m.aead, []byte(privateKey.String()), []byte(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedKey{
+		PublicKey:     publicKey,
+		EncryptedData: *data,
+	}, nil
+}
+
+// privateKey decrypts the private key for the public key, must be called with the lock held
+func (m *Keystore) privateKey(publicKey string) (*ecc.PrivateKey, error) {
+	if m.aead == nil {
+		return nil, ErrLocked
+	}
+	key := m.find(publicKey)
+	if key == nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyNotFound, publicKey)
+	}
+	wif, err := decrypt(m.aead, &key.EncryptedData, []byte(key.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed decrypting key: %v, error: %v", publicKey, err)
+	}
+	privateKey, err := ecc.NewPrivateKey(string(wif))
+	if err != nil {
+		return nil, fmt.Errorf("failed decrypting key: %v, invalid private key", publicKey)
+	}
+	return privateKey, nil
+}
+
+func removeKey(keys []*EncryptedKey, publicKey string) []*EncryptedKey {
+	filtered := make([]*EncryptedKey, 0, len(keys))
+	for _, key := range keys {
+		if key.PublicKey != publicKey {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
+
+func (m *Keystore) find(publicKey string) *EncryptedKey {
+	for _, key := range m.file.Keys {
+		if key.PublicKey == publicKey {
+			return key
+		}
+	}
+	return nil
+}
+
+// save must be called with the lock held
+func (m *Keystore) save() error {
+	content, err := json.MarshalIndent(m.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed marshalling keystore, error: %v", err)
+	}
+	// the file is replaced atomically, a failed write must not lose the only copy of the keys
+	err = util.WriteFileAtomic(m.path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("failed writing keystore: %v, error: %v", m.path, err)
+	}
+	return nil
+}
+
+func newKDFParams() (*KDFParams, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed generating keystore salt, error: %v", err)
+	}
+	return &KDFParams{
+		Name: kdfName,
+		N:    defaultScryptN,
+		R:    defaultScryptR,
+		P:    defaultScryptP,
+		Salt: hex.EncodeToString(salt),
+	}, nil
+}
+
+func deriveAEAD(params *KDFParams, passphrase string) (cipher.AEAD, error) {
+	if params.Name != kdfName {
+		return nil, fmt.Errorf("unsupported keystore kdf: %v", params.Name)
+	}
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt, error: %v", err)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed deriving keystore key, error: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encrypt(aead cipher.AEAD, plaintext, additionalData []byte) (*EncryptedData, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed generating nonce, error: %v", err)
+	}
+	return &EncryptedData{
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, additionalData)),
+	}, nil
+}
+
+func decrypt(aead cipher.AEAD, data *EncryptedData, additionalData []byte) ([]byte, error) {
+	nonce, err := hex.DecodeString(data.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+	ciphertext, err := hex.DecodeString(data.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed")
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,87 @@
+package keystore_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sebastianmontero/eos-go-toolbox/keystore"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func TestKeystore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := keystore.Create(path, "passphrase")
+	assert.NilError(t, err)
+	assert.Equal(t, len(ks.PublicKeys()), 0)
+	publicKey, err := ks.Add(service.EOSIOKey)
+	assert.NilError(t, err)
+	assert.Equal(t, publicKey.String(), service.GetEOSIOPublicKey().String())
+
+	ks, err = keystore.Open(path, "passphrase")
+	assert.NilError(t, err)
+	keys, err := ks.AvailableKeys(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(keys), 1)
+
+	_, err = keystore.Open(path, "wrong")
+	assert.Assert(t, errors.Is(err, keystore.ErrInvalidPassphrase))
+}
+
+func TestKeystoreAddInvalidKeyDoesNotLeakKey(t *testing.T) {
+	ks, err := keystore.Create(filepath.Join(t.TempDir(), "keystore.json"), "passphrase")
+	assert.NilError(t, err)
+	_, err = ks.Add("5Knotavalidkey")
+	assert.ErrorContains(t, err, "invalid private key")
+	assert.Assert(t, !strings.Contains(err.Error(), "5Knotavalidkey"))
+}
+
+func TestKeystoreRemoveRotateAndChangePassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := keystore.Create(path, "passphrase")
+	assert.NilError(t, err)
+	publicKey, err := ks.Add(service.EOSIOKey)
+	assert.NilError(t, err)
+
+	rotated, err := ks.Rotate(publicKey.String())
+	assert.NilError(t, err)
+	keys := ks.PublicKeys()
+	assert.Equal(t, len(keys), 2)
+	assert.Equal(t, keys[0].String(), publicKey.String())
+	assert.Equal(t, keys[1].String(), rotated.String())
+	assert.NilError(t, ks.Remove(publicKey.String()))
+	_, err = ks.Rotate(publicKey.String())
+	assert.Assert(t, errors.Is(err, keystore.ErrKeyNotFound))
+
+	assert.NilError(t, ks.ChangePassphrase("new passphrase"))
+	_, err = keystore.Open(path, "passphrase")
+	assert.Assert(t, errors.Is(err, keystore.ErrInvalidPassphrase))
+	ks, err = keystore.Open(path, "new passphrase")
+	assert.NilError(t, err)
+	assert.Equal(t, ks.PublicKeys()[0].String(), rotated.String())
+
+	assert.NilError(t, ks.Remove(rotated.String()))
+	assert.Equal(t, len(ks.PublicKeys()), 0)
+	err = ks.Remove(rotated.String())
+	assert.Assert(t, errors.Is(err, keystore.ErrKeyNotFound))
+
+	ks.Lock()
+	assert.Assert(t, ks.IsLocked())
+	_, err = ks.Add(service.EOSIOKey)
+	assert.Assert(t, errors.Is(err, keystore.ErrLocked))
+}
+
+func TestKeystoreFileDoesNotContainKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := keystore.Create(path, "passphrase")
+	assert.NilError(t, err)
+	_, err = ks.Add(service.EOSIOKey)
+	assert.NilError(t, err)
+	content, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(content), service.EOSIOKey))
+}
@@ -0,0 +1,36 @@
+package keystore
+
+import (
+	"context"
+	"fmt"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go/ecc"
+)
+
+// AvailableKeys implements eosc.Signer
+func (m *Keystore) AvailableKeys(ctx context.Context) ([]ecc.PublicKey, error) {
+	return m.PublicKeys(), nil
+}
+
+// Sign implements eosc.Signer, the required private keys are decrypted only for the duration of the signing
+func (m *Keystore) Sign(ctx context.Context, tx *eosc.SignedTransaction, chainID []byte, requiredKeys ...ecc.PublicKey) (*eosc.SignedTransaction, error) {
+	m.mutex.RLock()
+	keyBag := eosc.NewKeyBag()
+	for _, publicKey := range requiredKeys {
+		privateKey, err := m.privateKey(publicKey.String())
+		if err != nil {
+			m.mutex.RUnlock()
+			return nil, fmt.Errorf("failed signing transaction, error: %w", err)
+		}
+		keyBag.Append(privateKey)
+	}
+	m.mutex.RUnlock()
+	return keyBag.Sign(ctx, tx, chainID, requiredKeys...)
+}
+
+// ImportPrivateKey implements eosc.Signer, it adds the key to the keystore
+func (m *Keystore) ImportPrivateKey(ctx context.Context, wifPrivKey string) error {
+	_, err := m.Add(wifPrivKey)
+	return err
+}
@@ -0,0 +1,41 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	eostest "github.com/digital-scarcity/eos-go-test"
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+const testingEndpoint = "http://localhost:8888"
+
+// setupChain restarts nodeos with a fresh chain and returns a service signing with the default key
+func setupChain(t *testing.T) *service.EOS {
+	t.Log("Bootstrapping testing environment ...")
+	_, err := eostest.RestartNodeos(false,
+		"-e", "-p", "eosio",
+		"--plugin", "eosio::producer_plugin",
+		"--plugin", "eosio::producer_api_plugin",
+		"--plugin", "eosio::chain_api_plugin",
+		"--plugin", "eosio::http_plugin",
+		"--plugin", "eosio::trace_api_plugin",
+		"--trace-no-abis",
+		"--access-control-allow-origin", "*",
+		"--contracts-console",
+		"--http-validate-host", "false",
+		"--verbose-http-errors",
+		"--delete-all-blocks",
+	)
+	assert.NilError(t, err)
+
+	api, err := eosc.New(testingEndpoint)
+	assert.NilError(t, err)
+	keyBag := &eosc.KeyBag{}
+	err = keyBag.ImportPrivateKey(context.Background(), eostest.DefaultKey())
+	assert.NilError(t, err)
+	api.SetSigner(keyBag)
+	return service.NewEOS(api)
+}
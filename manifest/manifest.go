@@ -0,0 +1,132 @@
+// Package manifest describes an environment, its accounts, keys, contracts, permissions, links, tokens
+// and settings, in a YAML file and applies it to a chain pushing only the changes it requires
+package manifest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gopkg.in/yaml.v2"
+)
+
+type Account struct {
+	Name string `yaml:"name"`
+	// Creator pays for the account when it is created, defaults to eosio
+	Creator   string `yaml:"creator,omitempty"`
+	OwnerKey  string `yaml:"owner_key"`
+	ActiveKey string `yaml:"active_key,omitempty"`
+	// RAMBytes are only bought when the account is created
+	RAMBytes uint32 `yaml:"ram_bytes,omitempty"`
+	// EOSIOCode adds the eosio.code permission of the account to its active permission
+	EOSIOCode   bool                      `yaml:"eosio_code,omitempty"`
+	Permissions []*service.PermissionSpec `yaml:"permissions,omitempty"`
+	// Links if set are reconciled, links of the account that are not in the manifest are removed
+	Links []*service.PermissionLink `yaml:"links,omitempty"`
+}
+
+type Contract struct {
+	Account string `yaml:"account"`
+	// WASM and ABI paths are relative to the manifest file
+	WASM             string `yaml:"wasm"`
+	ABI              string `yaml:"abi"`
+	AllowBreakingABI bool   `yaml:"allow_breaking_abi,omitempty"`
+}
+
+type Token struct {
+	// Contract defaults to eosio.token
+	Contract  string `yaml:"contract,omitempty"`
+	Issuer    string `yaml:"issuer"`
+	MaxSupply string `yaml:"max_supply"`
+	// Issue is issued to the issuer when the token is created
+	Issue string `yaml:"issue,omitempty"`
+}
+
+type Setting struct {
+	Contract string `yaml:"contract"`
+	// Setter defaults to the contract
+	Setter string `yaml:"setter,omitempty"`
+	Key    string `yaml:"key"`
+	Type   string `yaml:"type"`
+	Value  string `yaml:"value"`
+}
+
+type Manifest struct {
+	Accounts  []*Account  `yaml:"accounts,omitempty"`
+	Contracts []*Contract `yaml:"contracts,omitempty"`
+	Tokens    []*Token    `yaml:"tokens,omitempty"`
+	Settings  []*Setting  `yaml:"settings,omitempty"`
+}
+
+// Load reads the manifest from a YAML file, the contract paths are made relative to the file
+func Load(path string) (*Manifest, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading manifest: %v, error: %v", path, err)
+	}
+	manifest, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading manifest: %v, error: %v", path, err)
+	}
+	baseDir := filepath.Dir(path)
+	for _, contract := range manifest.Contracts {
+		if !filepath.IsAbs(contract.WASM) {
+			contract.WASM = filepath.Join(baseDir, contract.WASM)
+		}
+		if !filepath.IsAbs(contract.ABI) {
+			contract.ABI = filepath.Join(baseDir, contract.ABI)
+		}
+	}
+	return manifest, nil
+}
+
+// Parse parses and validates the manifest, unknown fields are an error
+func Parse(content []byte) (*Manifest, error) {
+	manifest := &Manifest{}
+	err := yaml.UnmarshalStrict(content, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling manifest, error: %v", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (m *Manifest) Validate() error {
+	accounts := make(map[string]bool, len(m.Accounts))
+	for _, account := range m.Accounts {
+		if account.Name == "" {
+			return fmt.Errorf("invalid manifest, account name is required")
+		}
+		if accounts[account.Name] {
+			return fmt.Errorf("invalid manifest, account: %v is duplicated", account.Name)
+		}
+		accounts[account.Name] = true
+		if account.OwnerKey == "" {
+			return fmt.Errorf("invalid manifest, owner_key of account: %v is required", account.Name)
+		}
+		for _, permission := range account.Permissions {
+			if permission.Name == "" || permission.Name == "owner" || permission.Name == "active" {
+				return fmt.Errorf("invalid manifest, invalid permission: %q of account: %v, owner and active are set with the keys", permission.Name, account.Name)
+			}
+		}
+	}
+	for _, contract := range m.Contracts {
+		if contract.Account == "" || contract.WASM == "" || contract.ABI == "" {
+			return fmt.Errorf("invalid manifest, contracts require account, wasm and abi")
+		}
+	}
+	for _, token := range m.Tokens {
+		if token.Issuer == "" || token.MaxSupply == "" {
+			return fmt.Errorf("invalid manifest, tokens require issuer and max_supply")
+		}
+	}
+	for _, setting := range m.Settings {
+		if setting.Contract == "" || setting.Key == "" || setting.Type == "" {
+			return fmt.Errorf("invalid manifest, settings require contract, key and type")
+		}
+	}
+	return nil
+}
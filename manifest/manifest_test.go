@@ -0,0 +1,79 @@
+package manifest_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebastianmontero/eos-go-toolbox/manifest"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+const testManifest = `
+accounts:
+  - name: dao
+    owner_key: EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV
+    eosio_code: true
+    permissions:
+      - name: claim
+        accounts: [dao@eosio.code]
+    links:
+      - code: dao
+        action: claim
+        permission: claim
+contracts:
+  - account: dao
+    wasm: build/dao.wasm
+    abi: build/dao.abi
+tokens:
+  - issuer: dao
+    max_supply: 1000000.0000 HUSD
+    issue: 1000.0000 HUSD
+settings:
+  - contract: dao
+    key: voting_duration_sec
+    type: int64
+    value: "3600"
+`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	assert.NilError(t, ioutil.WriteFile(path, []byte(testManifest), 0644))
+	m, err := manifest.Load(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(m.Accounts), 1)
+	account := m.Accounts[0]
+	assert.Assert(t, account.EOSIOCode)
+	assert.DeepEqual(t, account.Permissions, []*service.PermissionSpec{{Name: "claim", Accounts: []string{"dao@eosio.code"}}})
+	assert.DeepEqual(t, account.Links, []*service.PermissionLink{{Code: "dao", Action: "claim", Permission: "claim"}})
+	assert.DeepEqual(t, m.Contracts, []*manifest.Contract{{
+		Account: "dao",
+		WASM:    filepath.Join(filepath.Dir(path), "build/dao.wasm"),
+		ABI:     filepath.Join(filepath.Dir(path), "build/dao.abi"),
+	}})
+	assert.DeepEqual(t, m.Tokens, []*manifest.Token{{Issuer: "dao", MaxSupply: "1000000.0000 HUSD", Issue: "1000.0000 HUSD"}})
+	assert.DeepEqual(t, m.Settings, []*manifest.Setting{{Contract: "dao", Key: "voting_duration_sec", Type: "int64", Value: "3600"}})
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := manifest.Parse([]byte("accounts:\n  - name: dao\n"))
+	assert.ErrorContains(t, err, "owner_key of account: dao is required")
+	_, err = manifest.Parse([]byte("accounts:\n  - name: dao\n    owner_key: EOS1\n    permissions:\n      - name: active\n"))
+	assert.ErrorContains(t, err, "owner and active are set with the keys")
+	_, err = manifest.Parse([]byte("contracts:\n  - account: dao\n"))
+	assert.ErrorContains(t, err, "contracts require account, wasm and abi")
+	_, err = manifest.Parse([]byte("unknown: true\n"))
+	assert.ErrorContains(t, err, "failed unmarshalling manifest")
+}
+
+func TestPlanString(t *testing.T) {
+	plan := &manifest.Plan{}
+	assert.Equal(t, plan.String(), "no changes")
+	plan.Changes = append(plan.Changes,
+		&manifest.Change{Kind: manifest.ChangeCreateAccount, Target: "dao", Description: "create account"},
+		&manifest.Change{Kind: manifest.ChangeSetSetting, Target: "dao voting_duration_sec", Description: "set to 3600"},
+	)
+	assert.Equal(t, plan.String(), "create-account dao: create account\nset-setting dao voting_duration_sec: set to 3600")
+	assert.Equal(t, len(plan.Actions()), 0)
+}
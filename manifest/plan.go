@@ -0,0 +1,391 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/contract"
+	"github.com/sebastianmontero/eos-go-toolbox/dto"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/system"
+	"github.com/sebastianmontero/eos-go/token"
+)
+
+const defaultTokenContract = "eosio.token"
+
+type ChangeKind string
+
+const (
+	ChangeCreateAccount    ChangeKind = "create-account"
+	ChangeUpdatePermission ChangeKind = "update-permission"
+	ChangeReconcileLinks   ChangeKind = "reconcile-links"
+	ChangeDeployContract   ChangeKind = "deploy-contract"
+	ChangeCreateToken      ChangeKind = "create-token"
+	ChangeSetSetting       ChangeKind = "set-setting"
+)
+
+// Change is a set of actions pushed in one transaction by Apply
+type Change struct {
+	Kind        ChangeKind     `json:"kind"`
+	Target      string         `json:"target"`
+	Description string         `json:"description"`
+	Actions     []*eosc.Action `json:"-"`
+	// TransactionID is set by Apply
+	TransactionID string `json:"transaction_id,omitempty"`
+}
+
+func (m *Change) String() string {
+	return fmt.Sprintf("%v %v: %v", m.Kind, m.Target, m.Description)
+}
+
+// Plan has the changes required for the chain to match the manifest, in the order they are applied
+type Plan struct {
+	Changes []*Change `json:"changes"`
+}
+
+func (m *Plan) IsEmpty() bool {
+	return len(m.Changes) == 0
+}
+
+func (m *Plan) Actions() []*eosc.Action {
+	actions := make([]*eosc.Action, 0)
+	for _, change := range m.Changes {
+		actions = append(actions, change.Actions...)
+	}
+	return actions
+}
+
+func (m *Plan) String() string {
+	if m.IsEmpty() {
+		return "no changes"
+	}
+	changes := make([]string, 0, len(m.Changes))
+	for _, change := range m.Changes {
+		changes = append(changes, change.String())
+	}
+	return strings.Join(changes, "\n")
+}
+
+func (m *Plan) add(kind ChangeKind, target, description string, actions ...*eosc.Action) {
+	m.Changes = append(m.Changes, &Change{
+		Kind:        kind,
+		Target:      target,
+		Description: description,
+		Actions:     actions,
+	})
+}
+
+// planner keeps the accounts that are created or get their first contract in the plan, their state
+// can not be read from the chain so all their changes are planned
+type planner struct {
+	eos   *service.EOS
+	plan  *Plan
+	fresh map[string]bool
+}
+
+// Plan diffs the manifest against the chain and returns the changes required, accounts are created,
+// then permissions and links are updated, contracts deployed, tokens created and settings set
+func (m *Manifest) Plan(eos *service.EOS) (*Plan, error) {
+	return m.PlanCtx(context.Background(), eos)
+}
+
+func (m *Manifest) PlanCtx(ctx context.Context, eos *service.EOS) (*Plan, error) {
+	p := &planner{
+		eos:   eos,
+		plan:  &Plan{Changes: make([]*Change, 0)},
+		fresh: make(map[string]bool),
+	}
+	for _, account := range m.Accounts {
+		if err := p.planAccount(ctx, account); err != nil {
+			return nil, err
+		}
+	}
+	for _, contract := range m.Contracts {
+		if err := p.planContract(ctx, contract); err != nil {
+			return nil, err
+		}
+	}
+	for _, token := range m.Tokens {
+		if err := p.planToken(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	for _, setting := range m.Settings {
+		if err := p.planSetting(setting); err != nil {
+			return nil, err
+		}
+	}
+	return p.plan, nil
+}
+
+// Apply pushes the changes of the plan, each one in its own transaction, returns the plan with the
+// transaction ids of the changes applied, which is also returned if a change fails
+func (m *Manifest) Apply(eos *service.EOS) (*Plan, error) {
+	return m.ApplyCtx(context.Background(), eos)
+}
+
+func (m *Manifest) ApplyCtx(ctx context.Context, eos *service.EOS) (*Plan, error) {
+	plan, err := m.PlanCtx(ctx, eos)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range plan.Changes {
+		resp, err := eos.TrxCtx(ctx, change.Actions...)
+		if err != nil {
+			return plan, fmt.Errorf("failed applying change: %v, error: %w", change, err)
+		}
+		change.TransactionID = resp.TransactionID
+	}
+	return plan, nil
+}
+
+// Propose proposes all the changes of the plan in one msig proposal, returns a nil response if there are no changes
+func (m *Manifest) Propose(eos *service.EOS, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration) (*Plan, *service.ProposeResponse, error) {
+	return m.ProposeCtx(context.Background(), eos, proposerName, requested, expireIn)
+}
+
+func (m *Manifest) ProposeCtx(ctx context.Context, eos *service.EOS, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration) (*Plan, *service.ProposeResponse, error) {
+	plan, err := m.PlanCtx(ctx, eos)
+	if err != nil {
+		return nil, nil, err
+	}
+	if plan.IsEmpty() {
+		return plan, nil, nil
+	}
+	response, err := eos.ProposeMultiSigCtx(ctx, proposerName, requested, expireIn, plan.Actions()...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error proposing manifest changes, error: %w", err)
+	}
+	return plan, response, nil
+}
+
+type desiredPermission struct {
+	name      string
+	parent    string
+	authority *eosc.Authority
+}
+
+func (m *planner) planAccount(ctx context.Context, account *Account) error {
+	owner, err := service.NewAuthorityBuilder(1).Key(account.OwnerKey, 1).Build()
+	if err != nil {
+		return fmt.Errorf("invalid owner key of account: %v, error: %v", account.Name, err)
+	}
+	activeKey := account.ActiveKey
+	if activeKey == "" {
+		activeKey = account.OwnerKey
+	}
+	activeBuilder := service.NewAuthorityBuilder(1).Key(activeKey, 1)
+	if account.EOSIOCode {
+		activeBuilder.Account(fmt.Sprintf("%v@eosio.code", account.Name), 1)
+	}
+	active, err := activeBuilder.Build()
+	if err != nil {
+		return fmt.Errorf("invalid active key of account: %v, error: %v", account.Name, err)
+	}
+	existing, err := m.eos.FindAccountCtx(ctx, account.Name)
+	if err != nil {
+		return fmt.Errorf("failed planning account: %v, error: %w", account.Name, err)
+	}
+	if existing == nil {
+		return m.planNewAccount(account, owner, active)
+	}
+	permissions := []*desiredPermission{
+		{name: "owner", authority: owner},
+		{name: "active", parent: "owner", authority: active},
+	}
+	for _, permission := range account.Permissions {
+		authority, err := permission.Authority()
+		if err != nil {
+			return fmt.Errorf("invalid permission: %v of account: %v, error: %v", permission.Name, account.Name, err)
+		}
+		permissions = append(permissions, &desiredPermission{
+			name:      permission.Name,
+			parent:    permission.ParentName(),
+			authority: authority,
+		})
+	}
+	for _, permission := range permissions {
+		action, diff, err := m.eos.GetUpdatePermissionActionCtx(ctx, account.Name, permission.name, permission.parent, permission.authority)
+		if err != nil {
+			return err
+		}
+		if action != nil {
+			m.plan.add(ChangeUpdatePermission, fmt.Sprintf("%v@%v", account.Name, permission.name), diff.String(), action)
+		}
+	}
+	if account.Links != nil {
+		actions, err := m.eos.GetReconcileLinksActionsCtx(ctx, account.Name, account.Links)
+		if err != nil {
+			return err
+		}
+		if len(actions) > 0 {
+			m.plan.add(ChangeReconcileLinks, account.Name, fmt.Sprintf("%v linkauth and unlinkauth actions", len(actions)), actions...)
+		}
+	}
+	return nil
+}
+
+// planNewAccount creates the account, its permissions and links are separate changes as nodeos checks the
+// authorizations before the transaction runs, so the account has to exist for them to be authorized
+func (m *planner) planNewAccount(account *Account, owner, active *eosc.Authority) error {
+	opts := &service.CreateAccountOpts{
+		Owner:    owner,
+		Active:   active,
+		RAMBytes: account.RAMBytes,
+	}
+	if account.Creator != "" {
+		opts.Creator = account.Creator
+	}
+	actions, err := m.eos.GetCreateAccountActions(account.Name, opts)
+	if err != nil {
+		return err
+	}
+	m.fresh[account.Name] = true
+	m.plan.add(ChangeCreateAccount, account.Name, "create account", actions...)
+	for _, permission := range account.Permissions {
+		authority, err := permission.Authority()
+		if err != nil {
+			return fmt.Errorf("invalid permission: %v of account: %v, error: %v", permission.Name, account.Name, err)
+		}
+		m.plan.add(ChangeUpdatePermission, fmt.Sprintf("%v@%v", account.Name, permission.Name), "create permission",
+			system.NewUpdateAuth(eosc.AN(account.Name), eosc.PN(permission.Name), eosc.PN(permission.ParentName()), *authority, "active"))
+	}
+	if len(account.Links) > 0 {
+		links := make([]*eosc.Action, 0, len(account.Links))
+		for _, link := range account.Links {
+			links = append(links, system.NewLinkAuth(eosc.AN(account.Name), link.Code, link.Action, link.Permission))
+		}
+		m.plan.add(ChangeReconcileLinks, account.Name, fmt.Sprintf("%v linkauth actions", len(links)), links...)
+	}
+	return nil
+}
+
+func (m *planner) planContract(ctx context.Context, contract *Contract) error {
+	if m.fresh[contract.Account] {
+		actions, err := m.eos.GetSetContractActions(contract.Account, contract.WASM, contract.ABI)
+		if err != nil {
+			return err
+		}
+		m.plan.add(ChangeDeployContract, contract.Account, "deploy code and abi", actions...)
+		return nil
+	}
+	actions, report, err := m.eos.GetDeployContractActionsCtx(ctx, contract.Account, contract.WASM, contract.ABI)
+	if err != nil {
+		return err
+	}
+	if !contract.AllowBreakingABI {
+		if err := report.BreakingABIError(); err != nil {
+			return err
+		}
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	if strings.Trim(report.PreviousCodeHash, "0") == "" {
+		m.fresh[contract.Account] = true
+	}
+	updated := make([]string, 0, 2)
+	if report.CodeUpdated {
+		updated = append(updated, fmt.Sprintf("code %v -> %v", report.PreviousCodeHash, report.CodeHash))
+	}
+	if report.ABIUpdated {
+		updated = append(updated, fmt.Sprintf("abi with %v changes", len(report.ABIDiff.Changes)))
+	}
+	m.plan.add(ChangeDeployContract, contract.Account, "deploy "+strings.Join(updated, " and "), actions...)
+	return nil
+}
+
+// planToken creates the token if it does not exist, the issuer and max supply of existing tokens can not be changed
+func (m *planner) planToken(ctx context.Context, tkn *Token) error {
+	tokenContract := tkn.Contract
+	if tokenContract == "" {
+		tokenContract = defaultTokenContract
+	}
+	issuer, err := util.ToAccountName(tkn.Issuer)
+	if err != nil {
+		return err
+	}
+	maxSupply, err := util.ToAsset(tkn.MaxSupply)
+	if err != nil {
+		return fmt.Errorf("invalid max supply of token: %v, error: %v", tkn.MaxSupply, err)
+	}
+	target := fmt.Sprintf("%v %v", tokenContract, maxSupply.Symbol.Symbol)
+	if !m.fresh[tokenContract] {
+		stat, err := m.eos.GetCurrencyStatCtx(ctx, maxSupply.Symbol, tokenContract)
+		if err != nil {
+			return fmt.Errorf("failed planning token: %v, error: %v", target, err)
+		}
+		if stat != nil {
+			if stat.Issuer != issuer || stat.MaxSupply != maxSupply {
+				return fmt.Errorf("token: %v already exists with issuer: %v and max supply: %v", target, stat.Issuer, stat.MaxSupply)
+			}
+			return nil
+		}
+	}
+	create, err := m.eos.BuildAction(tokenContract, "create", tokenContract, &token.Create{
+		Issuer:        issuer,
+		MaximumSupply: maxSupply,
+	})
+	if err != nil {
+		return err
+	}
+	actions := []*eosc.Action{create}
+	description := fmt.Sprintf("create token with issuer: %v and max supply: %v", issuer, maxSupply)
+	if tkn.Issue != "" {
+		quantity, err := util.ToAsset(tkn.Issue)
+		if err != nil {
+			return fmt.Errorf("invalid issue of token: %v, error: %v", target, err)
+		}
+		issue, err := m.eos.BuildAction(tokenContract, "issue", issuer, &token.Issue{
+			To:       issuer,
+			Quantity: quantity,
+		})
+		if err != nil {
+			return err
+		}
+		actions = append(actions, issue)
+		description += fmt.Sprintf(", issue: %v", quantity)
+	}
+	m.plan.add(ChangeCreateToken, target, description, actions...)
+	return nil
+}
+
+func (m *planner) planSetting(setting *Setting) error {
+	value, err := dto.ParseToFlexValue(setting.Type, setting.Value)
+	if err != nil {
+		return fmt.Errorf("invalid setting: %v of contract: %v, error: %v", setting.Key, setting.Contract, err)
+	}
+	settingsContract := contract.NewSettingsContract(m.eos, setting.Contract)
+	target := fmt.Sprintf("%v %v", setting.Contract, setting.Key)
+	description := fmt.Sprintf("set to %v", value)
+	if !m.fresh[setting.Contract] {
+		current, err := settingsContract.FindSetting(setting.Key)
+		if err != nil {
+			return fmt.Errorf("failed planning setting: %v, error: %v", target, err)
+		}
+		if current != nil && len(current.Values) > 0 {
+			if current.Values[0].IsEqual(value) {
+				return nil
+			}
+			description = fmt.Sprintf("change from %v to %v", current.Values[0], value)
+		}
+	}
+	setter := setting.Setter
+	if setter == "" {
+		setter = setting.Contract
+	}
+	action, err := settingsContract.BuildAction("setsetting", setter, &contract.ModifySettingArgs{
+		Setter: eosc.AN(setter),
+		Key:    setting.Key,
+		Value:  value,
+	})
+	if err != nil {
+		return err
+	}
+	m.plan.add(ChangeSetSetting, target, description, action)
+	return nil
+}
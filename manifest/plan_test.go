@@ -0,0 +1,79 @@
+package manifest_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sebastianmontero/eos-go-toolbox/manifest"
+	"github.com/sebastianmontero/eos-go-toolbox/test"
+	"gotest.tools/assert"
+)
+
+const applyManifest = `
+accounts:
+  - name: member
+    owner_key: EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV
+  - name: dao
+    owner_key: EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV
+    eosio_code: true
+    permissions:
+      - name: claim
+        accounts: [dao@eosio.code]
+    links:
+      - code: dao
+        action: claim
+        permission: claim
+contracts:
+  - account: dao
+    wasm: %v
+    abi: %v
+`
+
+func TestApplyAndReplan(t *testing.T) {
+	eos := setupChain(t)
+	wasmFile, abiFile := test.WriteContract(t, t.TempDir(), 1, "claim")
+	m, err := manifest.Parse([]byte(fmt.Sprintf(applyManifest, wasmFile, abiFile)))
+	assert.NilError(t, err)
+
+	// dao is created in the plan so its contract is planned without reading the chain
+	plan, err := m.Plan(eos)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.String(), "create-account member: create account\n"+
+		"create-account dao: create account\n"+
+		"update-permission dao@claim: create permission\n"+
+		"reconcile-links dao: 1 linkauth actions\n"+
+		"deploy-contract dao: deploy code and abi")
+
+	plan, err = m.Apply(eos)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan.Changes), 5)
+	for _, change := range plan.Changes {
+		assert.Assert(t, change.TransactionID != "", "change: %v has no transaction id", change)
+	}
+
+	plan, err = m.Plan(eos)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.IsEmpty(), "expected no changes, plan: %v", plan)
+
+	plan, response, err := m.Propose(eos, "dao", nil, time.Hour)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.IsEmpty())
+	assert.Assert(t, response == nil)
+
+	m.Accounts[1].Permissions[0].Accounts = []string{"member@active"}
+	plan, err = m.Plan(eos)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan.Changes), 1, "plan: %v", plan)
+	change := plan.Changes[0]
+	assert.Equal(t, change.Kind, manifest.ChangeUpdatePermission)
+	assert.Equal(t, change.Target, "dao@claim")
+	assert.Equal(t, len(change.Actions), 1)
+
+	plan, err = m.Apply(eos)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan.Changes), 1)
+	plan, err = m.Plan(eos)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.IsEmpty(), "expected no changes, plan: %v", plan)
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+)
+
+type ABIChangeKind string
+
+const (
+	ABIChangeAdded   ABIChangeKind = "added"
+	ABIChangeRemoved ABIChangeKind = "removed"
+	ABIChangeChanged ABIChangeKind = "changed"
+)
+
+// ABI elements
+const (
+	ABIElementAction  = "action"
+	ABIElementTable   = "table"
+	ABIElementStruct  = "struct"
+	ABIElementField   = "field"
+	ABIElementType    = "type"
+	ABIElementVariant = "variant"
+)
+
+// ABIChange is a change of an element of the ABI, fields are named struct.field, a change is breaking
+// if data serialized with the old ABI, such as existing table rows, or clients using it could fail
+type ABIChange struct {
+	Kind     ABIChangeKind `json:"kind"`
+	Element  string        `json:"element"`
+	Name     string        `json:"name"`
+	From     string        `json:"from,omitempty"`
+	To       string        `json:"to,omitempty"`
+	Breaking bool          `json:"breaking"`
+}
+
+func (m *ABIChange) String() string {
+	compatibility := "compatible"
+	if m.Breaking {
+		compatibility = "breaking"
+	}
+	change := fmt.Sprintf("[%v] %v %v: %v", compatibility, m.Kind, m.Element, m.Name)
+	if m.Kind == ABIChangeChanged {
+		change += fmt.Sprintf(", %v -> %v", m.From, m.To)
+	}
+	return change
+}
+
+type ABIDiff struct {
+	Changes []*ABIChange `json:"changes"`
+}
+
+func (m *ABIDiff) IsEmpty() bool {
+	return len(m.Changes) == 0
+}
+
+func (m *ABIDiff) IsBreaking() bool {
+	return len(m.Breaking()) > 0
+}
+
+func (m *ABIDiff) Breaking() []*ABIChange {
+	breaking := make([]*ABIChange, 0)
+	for _, change := range m.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// BreakingABIError returns a BreakingABIError for the account if the diff has breaking changes, nil otherwise
+func (m *ABIDiff) BreakingABIError(account eosc.AccountName) error {
+	if !m.IsBreaking() {
+		return nil
+	}
+	breakingErr := &eoserr.BreakingABIError{Account: string(account)}
+	for _, change := range m.Breaking() {
+		breakingErr.Changes = append(breakingErr.Changes, change.String())
+	}
+	return breakingErr
+}
+
+func (m *ABIDiff) String() string {
+	changes := make([]string, 0, len(m.Changes))
+	for _, change := range m.Changes {
+		changes = append(changes, change.String())
+	}
+	return strings.Join(changes, "\n")
+}
+
+func (m *ABIDiff) add(kind ABIChangeKind, element, name, from, to string, breaking bool) {
+	m.Changes = append(m.Changes, &ABIChange{
+		Kind:     kind,
+		Element:  element,
+		Name:     name,
+		From:     from,
+		To:       to,
+		Breaking: breaking,
+	})
+}
+
+// DiffABI compares the ABIs, current can be nil if the account has no ABI. Removing or changing
+// actions, tables, structs, fields, types and variants is breaking, adding them is compatible except for
+// fields appended to existing structs which are only compatible if they are binary extensions ($ suffix),
+// renamed fields are breaking because they change the JSON form of the data, ricardian contracts are ignored
+func DiffABI(current, desired *eosc.ABI) *ABIDiff {
+	diff := &ABIDiff{Changes: make([]*ABIChange, 0)}
+	if current == nil {
+		current = &eosc.ABI{}
+	}
+	diffTypes(diff, current.Types, desired.Types)
+	diffStructs(diff, current.Structs, desired.Structs)
+	diffActions(diff, current.Actions, desired.Actions)
+	diffTables(diff, current.Tables, desired.Tables)
+	diffVariants(diff, current.Variants, desired.Variants)
+	return diff
+}
+
+func diffTypes(diff *ABIDiff, current, desired []eosc.ABIType) {
+	desiredTypes := make(map[string]eosc.ABIType, len(desired))
+	for _, abiType := range desired {
+		desiredTypes[abiType.NewTypeName] = abiType
+	}
+	currentTypes := make(map[string]bool, len(current))
+	for _, abiType := range current {
+		currentTypes[abiType.NewTypeName] = true
+		desiredType, ok := desiredTypes[abiType.NewTypeName]
+		if !ok {
+			diff.add(ABIChangeRemoved, ABIElementType, abiType.NewTypeName, abiType.Type, "", true)
+		} else if desiredType.Type != abiType.Type {
+			diff.add(ABIChangeChanged, ABIElementType, abiType.NewTypeName, abiType.Type, desiredType.Type, true)
+		}
+	}
+	for _, abiType := range desired {
+		if !currentTypes[abiType.NewTypeName] {
+			diff.add(ABIChangeAdded, ABIElementType, abiType.NewTypeName, "", abiType.Type, false)
+		}
+	}
+}
+
+func diffStructs(diff *ABIDiff, current, desired []eosc.StructDef) {
+	desiredStructs := make(map[string]eosc.StructDef, len(desired))
+	for _, structDef := range desired {
+		desiredStructs[structDef.Name] = structDef
+	}
+	currentStructs := make(map[string]bool, len(current))
+	for _, structDef := range current {
+		currentStructs[structDef.Name] = true
+		desiredStruct, ok := desiredStructs[structDef.Name]
+		if !ok {
+			diff.add(ABIChangeRemoved, ABIElementStruct, structDef.Name, "", "", true)
+			continue
+		}
+		if desiredStruct.Base != structDef.Base {
+			diff.add(ABIChangeChanged, ABIElementStruct, structDef.Name, "base: "+structDef.Base, "base: "+desiredStruct.Base, true)
+		}
+		diffFields(diff, structDef.Name, structDef.Fields, desiredStruct.Fields)
+	}
+	for _, structDef := range desired {
+		if !currentStructs[structDef.Name] {
+			diff.add(ABIChangeAdded, ABIElementStruct, structDef.Name, "", "", false)
+		}
+	}
+}
+
+// diffFields compares the fields by position, as that is how they are serialized
+func diffFields(diff *ABIDiff, structName string, current, desired []eosc.FieldDef) {
+	common := len(current)
+	if len(desired) < common {
+		common = len(desired)
+	}
+	for i := 0; i < common; i++ {
+		from, to := current[i], desired[i]
+		if from.Name != to.Name {
+			diff.add(ABIChangeChanged, ABIElementField, fieldName(structName, from), "name: "+from.Name, "name: "+to.Name, true)
+		}
+		if from.Type != to.Type {
+			diff.add(ABIChangeChanged, ABIElementField, fieldName(structName, to), from.Type, to.Type, true)
+		}
+	}
+	for _, field := range current[common:] {
+		diff.add(ABIChangeRemoved, ABIElementField, fieldName(structName, field), field.Type, "", true)
+	}
+	for _, field := range desired[common:] {
+		diff.add(ABIChangeAdded, ABIElementField, fieldName(structName, field), "", field.Type, !strings.HasSuffix(field.Type, "$"))
+	}
+}
+
+func fieldName(structName string, field eosc.FieldDef) string {
+	return fmt.Sprintf("%v.%v", structName, field.Name)
+}
+
+func diffActions(diff *ABIDiff, current, desired []eosc.ActionDef) {
+	desiredActions := make(map[eosc.ActionName]eosc.ActionDef, len(desired))
+	for _, action := range desired {
+		desiredActions[action.Name] = action
+	}
+	currentActions := make(map[eosc.ActionName]bool, len(current))
+	for _, action := range current {
+		currentActions[action.Name] = true
+		desiredAction, ok := desiredActions[action.Name]
+		if !ok {
+			diff.add(ABIChangeRemoved, ABIElementAction, string(action.Name), action.Type, "", true)
+		} else if desiredAction.Type != action.Type {
+			diff.add(ABIChangeChanged, ABIElementAction, string(action.Name), action.Type, desiredAction.Type, true)
+		}
+	}
+	for _, action := range desired {
+		if !currentActions[action.Name] {
+			diff.add(ABIChangeAdded, ABIElementAction, string(action.Name), "", action.Type, false)
+		}
+	}
+}
+
+func diffTables(diff *ABIDiff, current, desired []eosc.TableDef) {
+	desiredTables := make(map[eosc.TableName]eosc.TableDef, len(desired))
+	for _, table := range desired {
+		desiredTables[table.Name] = table
+	}
+	currentTables := make(map[eosc.TableName]bool, len(current))
+	for _, table := range current {
+		currentTables[table.Name] = true
+		desiredTable, ok := desiredTables[table.Name]
+		if !ok {
+			diff.add(ABIChangeRemoved, ABIElementTable, string(table.Name), table.Type, "", true)
+			continue
+		}
+		if desiredTable.Type != table.Type {
+			diff.add(ABIChangeChanged, ABIElementTable, string(table.Name), table.Type, desiredTable.Type, true)
+		}
+		if from, to := tableIndex(table), tableIndex(desiredTable); from != to {
+			diff.add(ABIChangeChanged, ABIElementTable, string(table.Name), from, to, true)
+		}
+	}
+	for _, table := range desired {
+		if !currentTables[table.Name] {
+			diff.add(ABIChangeAdded, ABIElementTable, string(table.Name), "", table.Type, false)
+		}
+	}
+}
+
+func tableIndex(table eosc.TableDef) string {
+	return fmt.Sprintf("index: %v(%v: %v)", table.IndexType, strings.Join(table.KeyNames, ","), strings.Join(table.KeyTypes, ","))
+}
+
+// diffVariants treats appending types to a variant as compatible, as the existing indexes do not change
+func diffVariants(diff *ABIDiff, current, desired []eosc.VariantDef) {
+	desiredVariants := make(map[string]eosc.VariantDef, len(desired))
+	for _, variant := range desired {
+		desiredVariants[variant.Name] = variant
+	}
+	currentVariants := make(map[string]bool, len(current))
+	for _, variant := range current {
+		currentVariants[variant.Name] = true
+		desiredVariant, ok := desiredVariants[variant.Name]
+		if !ok {
+			diff.add(ABIChangeRemoved, ABIElementVariant, variant.Name, strings.Join(variant.Types, ","), "", true)
+			continue
+		}
+		from, to := strings.Join(variant.Types, ","), strings.Join(desiredVariant.Types, ",")
+		if from == to {
+			continue
+		}
+		appended := len(desiredVariant.Types) > len(variant.Types) && strings.HasPrefix(to, from+",")
+		diff.add(ABIChangeChanged, ABIElementVariant, variant.Name, from, to, !appended)
+	}
+	for _, variant := range desired {
+		if !currentVariants[variant.Name] {
+			diff.add(ABIChangeAdded, ABIElementVariant, variant.Name, "", strings.Join(variant.Types, ","), false)
+		}
+	}
+}
+
+// LoadABIFile reads the ABI from a JSON file
+func LoadABIFile(abiFile string) (*eosc.ABI, error) {
+	content, err := ioutil.ReadFile(abiFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading abi file: %v, error: %v", abiFile, err)
+	}
+	abi := &eosc.ABI{}
+	err = json.Unmarshal(content, abi)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling abi file: %v, error: %v", abiFile, err)
+	}
+	return abi, nil
+}
+
+// GetABI returns the ABI of the account, or nil if it has no ABI
+func (m *EOS) GetABI(accountName interface{}) (*eosc.ABI, error) {
+	return m.GetABICtx(context.Background(), accountName)
+}
+
+func (m *EOS) GetABICtx(ctx context.Context, accountName interface{}) (*eosc.ABI, error) {
+	account, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	var resp *eosc.GetABIResp
+	err = m.withRetries(ctx, func() (err error) {
+		resp, err = m.API.GetABI(ctx, account)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed getting abi of account: %v, error: %w", account, err)
+	}
+	if resp.ABI.Version == "" {
+		return nil, nil
+	}
+	return &resp.ABI, nil
+}
+
+// DiffContractABI compares the ABI of the account on chain with the ABI file
+func (m *EOS) DiffContractABI(accountName interface{}, abiFile string) (*ABIDiff, error) {
+	return m.DiffContractABICtx(context.Background(), accountName, abiFile)
+}
+
+func (m *EOS) DiffContractABICtx(ctx context.Context, accountName interface{}, abiFile string) (*ABIDiff, error) {
+	desired, err := LoadABIFile(abiFile)
+	if err != nil {
+		return nil, err
+	}
+	current, err := m.GetABICtx(ctx, accountName)
+	if err != nil {
+		return nil, err
+	}
+	return DiffABI(current, desired), nil
+}
@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func newTestABI() *eosc.ABI {
+	return &eosc.ABI{
+		Version: "eosio::abi/1.1",
+		Types:   []eosc.ABIType{{NewTypeName: "amount", Type: "uint64"}},
+		Structs: []eosc.StructDef{
+			{Name: "account", Fields: []eosc.FieldDef{{Name: "owner", Type: "name"}, {Name: "balance", Type: "asset"}}},
+			{Name: "transfer", Fields: []eosc.FieldDef{{Name: "from", Type: "name"}, {Name: "to", Type: "name"}}},
+		},
+		Actions:  []eosc.ActionDef{{Name: "transfer", Type: "transfer"}},
+		Tables:   []eosc.TableDef{{Name: "accounts", IndexType: "i64", Type: "account"}},
+		Variants: []eosc.VariantDef{{Name: "value", Types: []string{"uint64", "string"}}},
+	}
+}
+
+func TestDiffABICompatible(t *testing.T) {
+	current := newTestABI()
+	desired := newTestABI()
+	assert.Assert(t, service.DiffABI(current, desired).IsEmpty())
+
+	desired.Structs[0].Fields = append(desired.Structs[0].Fields, eosc.FieldDef{Name: "memo", Type: "string$"})
+	desired.Structs = append(desired.Structs, eosc.StructDef{Name: "close"})
+	desired.Actions = append(desired.Actions, eosc.ActionDef{Name: "close", Type: "close"})
+	desired.Variants[0].Types = append(desired.Variants[0].Types, "bool")
+	diff := service.DiffABI(current, desired)
+	assert.Assert(t, !diff.IsBreaking())
+	assert.DeepEqual(t, diff.Changes, []*service.ABIChange{
+		{Kind: service.ABIChangeAdded, Element: service.ABIElementField, Name: "account.memo", To: "string$"},
+		{Kind: service.ABIChangeAdded, Element: service.ABIElementStruct, Name: "close"},
+		{Kind: service.ABIChangeAdded, Element: service.ABIElementAction, Name: "close", To: "close"},
+		{Kind: service.ABIChangeChanged, Element: service.ABIElementVariant, Name: "value", From: "uint64,string", To: "uint64,string,bool"},
+	})
+
+	diff = service.DiffABI(nil, desired)
+	assert.Assert(t, !diff.IsBreaking())
+	assert.Equal(t, len(diff.Changes), 8)
+}
+
+func TestDiffABIBreaking(t *testing.T) {
+	current := newTestABI()
+	desired := newTestABI()
+	desired.Types[0].Type = "uint32"
+	desired.Structs[0].Fields = []eosc.FieldDef{{Name: "owner", Type: "name"}, {Name: "amount", Type: "uint64"}, {Name: "memo", Type: "string"}}
+	desired.Structs[1].Fields = desired.Structs[1].Fields[:1]
+	desired.Actions = nil
+	desired.Tables[0].IndexType = "i128"
+	desired.Variants[0].Types = []string{"string", "uint64"}
+	diff := service.DiffABI(current, desired)
+	assert.Assert(t, diff.IsBreaking())
+	assert.Equal(t, len(diff.Breaking()), len(diff.Changes))
+	assert.DeepEqual(t, diff.Changes, []*service.ABIChange{
+		{Kind: service.ABIChangeChanged, Element: service.ABIElementType, Name: "amount", From: "uint64", To: "uint32", Breaking: true},
+		{Kind: service.ABIChangeChanged, Element: service.ABIElementField, Name: "account.balance", From: "name: balance", To: "name: amount", Breaking: true},
+		{Kind: service.ABIChangeChanged, Element: service.ABIElementField, Name: "account.amount", From: "asset", To: "uint64", Breaking: true},
+		{Kind: service.ABIChangeAdded, Element: service.ABIElementField, Name: "account.memo", To: "string", Breaking: true},
+		{Kind: service.ABIChangeRemoved, Element: service.ABIElementField, Name: "transfer.to", From: "name", Breaking: true},
+		{Kind: service.ABIChangeRemoved, Element: service.ABIElementAction, Name: "transfer", From: "transfer", Breaking: true},
+		{Kind: service.ABIChangeChanged, Element: service.ABIElementTable, Name: "accounts", From: "index: i64(: )", To: "index: i128(: )", Breaking: true},
+		{Kind: service.ABIChangeChanged, Element: service.ABIElementVariant, Name: "value", From: "uint64,string", To: "string,uint64", Breaking: true},
+	})
+	assert.Equal(t, diff.Changes[2].String(), "[breaking] changed field: account.amount, asset -> uint64")
+	var breakingErr *eoserr.BreakingABIError
+	assert.Assert(t, errors.As(diff.BreakingABIError("contracta"), &breakingErr))
+	assert.Equal(t, len(breakingErr.Changes), len(diff.Changes))
+	assert.NilError(t, service.DiffABI(current, current).BreakingABIError("contracta"))
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+)
+
+// AuthorityBuilder builds a valid authority, sorted in the canonical order required by nodeos
+type AuthorityBuilder struct {
+	authority *eosc.Authority
+	err       error
+}
+
+func NewAuthorityBuilder(threshold uint32) *AuthorityBuilder {
+	return &AuthorityBuilder{
+		authority: &eosc.Authority{
+			Threshold: threshold,
+			Keys:      make([]eosc.KeyWeight, 0),
+			Accounts:  make([]eosc.PermissionLevelWeight, 0),
+			Waits:     make([]eosc.WaitWeight, 0),
+		},
+	}
+}
+
+// Key adds a key, publicKey can be a string, ecc.PublicKey or *ecc.PublicKey
+func (m *AuthorityBuilder) Key(publicKey interface{}, weight uint16) *AuthorityBuilder {
+	key, err := util.ToPublicKey(publicKey)
+	if err != nil {
+		m.setErr(err)
+		return m
+	}
+	m.authority.Keys = append(m.authority.Keys, eosc.KeyWeight{
+		PublicKey: key,
+		Weight:    weight,
+	})
+	return m
+}
+
+// Account adds an account permission, permissionLevel can be a string in actor@permission format
+// or an eosc.PermissionLevel
+func (m *AuthorityBuilder) Account(permissionLevel interface{}, weight uint16) *AuthorityBuilder {
+	level, err := util.ToPermissionLevel(permissionLevel)
+	if err != nil {
+		m.setErr(err)
+		return m
+	}
+	m.authority.Accounts = append(m.authority.Accounts, eosc.PermissionLevelWeight{
+		Permission: level,
+		Weight:     weight,
+	})
+	return m
+}
+
+func (m *AuthorityBuilder) Wait(waitSec uint32, weight uint16) *AuthorityBuilder {
+	m.authority.Waits = append(m.authority.Waits, eosc.WaitWeight{
+		WaitSec: waitSec,
+		Weight:  weight,
+	})
+	return m
+}
+
+// Build sorts and validates the authority
+func (m *AuthorityBuilder) Build() (*eosc.Authority, error) {
+	if m.err != nil {
+		return nil, fmt.Errorf("failed building authority, error: %v", m.err)
+	}
+	authority := CopyAuthority(m.authority)
+	SortAuthority(authority)
+	if err := ValidateAuthority(authority); err != nil {
+		return nil, err
+	}
+	return authority, nil
+}
+
+func (m *AuthorityBuilder) setErr(err error) {
+	if m.err == nil {
+		m.err = err
+	}
+}
+
+// SortAuthority sorts the keys, accounts and waits of the authority in the order required by nodeos
+func SortAuthority(authority *eosc.Authority) {
+	sort.SliceStable(authority.Keys, func(i, j int) bool {
+		return comparePublicKeys(authority.Keys[i], authority.Keys[j]) < 0
+	})
+	sort.SliceStable(authority.Accounts, func(i, j int) bool {
+		return comparePermissionLevels(authority.Accounts[i].Permission, authority.Accounts[j].Permission) < 0
+	})
+	sort.SliceStable(authority.Waits, func(i, j int) bool {
+		return authority.Waits[i].WaitSec < authority.Waits[j].WaitSec
+	})
+}
+
+// ValidateAuthority checks the authority would be accepted by nodeos, it must be sorted, have no
+// duplicates nor zero weights, and its threshold must be reachable
+func ValidateAuthority(authority *eosc.Authority) error {
+	if authority.Threshold == 0 {
+		return fmt.Errorf("invalid authority, threshold must be greater than zero")
+	}
+	var totalWeight uint32
+	for i, key := range authority.Keys {
+		if key.Weight == 0 {
+			return fmt.Errorf("invalid authority, key: %v has zero weight", key.PublicKey)
+		}
+		if i > 0 && comparePublicKeys(authority.Keys[i-1], key) >= 0 {
+			return fmt.Errorf("invalid authority, keys are not sorted or key: %v is duplicated", key.PublicKey)
+		}
+		totalWeight += uint32(key.Weight)
+	}
+	for i, account := range authority.Accounts {
+		if account.Weight == 0 {
+			return fmt.Errorf("invalid authority, account: %v has zero weight", account.Permission)
+		}
+		if i > 0 && comparePermissionLevels(authority.Accounts[i-1].Permission, account.Permission) >= 0 {
+			return fmt.Errorf("invalid authority, accounts are not sorted or account: %v is duplicated", account.Permission)
+		}
+		totalWeight += uint32(account.Weight)
+	}
+	for i, wait := range authority.Waits {
+		if wait.Weight == 0 {
+			return fmt.Errorf("invalid authority, wait: %v has zero weight", wait.WaitSec)
+		}
+		if wait.WaitSec == 0 {
+			return fmt.Errorf("invalid authority, wait seconds must be greater than zero")
+		}
+		if i > 0 && authority.Waits[i-1].WaitSec > wait.WaitSec {
+			return fmt.Errorf("invalid authority, waits are not sorted")
+		}
+		totalWeight += uint32(wait.Weight)
+	}
+	if totalWeight < authority.Threshold {
+		return fmt.Errorf("invalid authority, the total weight: %v is lower than the threshold: %v", totalWeight, authority.Threshold)
+	}
+	return nil
+}
+
+func CopyAuthority(authority *eosc.Authority) *eosc.Authority {
+	return &eosc.Authority{
+		Threshold: authority.Threshold,
+		Keys:      append(make([]eosc.KeyWeight, 0, len(authority.Keys)), authority.Keys...),
+		Accounts:  append(make([]eosc.PermissionLevelWeight, 0, len(authority.Accounts)), authority.Accounts...),
+		Waits:     append(make([]eosc.WaitWeight, 0, len(authority.Waits)), authority.Waits...),
+	}
+}
+
+// AuthorityDiff contains the changes required to go from one authority to another, a change
+// of weight shows up as a removal and an addition
+type AuthorityDiff struct {
+	ThresholdFrom   uint32
+	ThresholdTo     uint32
+	KeysAdded       []eosc.KeyWeight
+	KeysRemoved     []eosc.KeyWeight
+	AccountsAdded   []eosc.PermissionLevelWeight
+	AccountsRemoved []eosc.PermissionLevelWeight
+	WaitsAdded      []eosc.WaitWeight
+	WaitsRemoved    []eosc.WaitWeight
+}
+
+// DiffAuthority compares the authorities ignoring the order of their elements, current can be nil
+// for a permission that does not exist yet
+func DiffAuthority(current, desired *eosc.Authority) *AuthorityDiff {
+	if current == nil {
+		current = &eosc.Authority{}
+	}
+	diff := &AuthorityDiff{
+		ThresholdFrom: current.Threshold,
+		ThresholdTo:   desired.Threshold,
+	}
+	currentKeys := make(map[string]bool, len(current.Keys))
+	for _, key := range current.Keys {
+		currentKeys[keyWeightID(key)] = true
+	}
+	desiredKeys := make(map[string]bool, len(desired.Keys))
+	for _, key := range desired.Keys {
+		desiredKeys[keyWeightID(key)] = true
+		if !currentKeys[keyWeightID(key)] {
+			diff.KeysAdded = append(diff.KeysAdded, key)
+		}
+	}
+	for _, key := range current.Keys {
+		if !desiredKeys[keyWeightID(key)] {
+			diff.KeysRemoved = append(diff.KeysRemoved, key)
+		}
+	}
+	currentAccounts := make(map[eosc.PermissionLevelWeight]bool, len(current.Accounts))
+	for _, account := range current.Accounts {
+		currentAccounts[account] = true
+	}
+	desiredAccounts := make(map[eosc.PermissionLevelWeight]bool, len(desired.Accounts))
+	for _, account := range desired.Accounts {
+		desiredAccounts[account] = true
+		if !currentAccounts[account] {
+			diff.AccountsAdded = append(diff.AccountsAdded, account)
+		}
+	}
+	for _, account := range current.Accounts {
+		if !desiredAccounts[account] {
+			diff.AccountsRemoved = append(diff.AccountsRemoved, account)
+		}
+	}
+	currentWaits := make(map[eosc.WaitWeight]bool, len(current.Waits))
+	for _, wait := range current.Waits {
+		currentWaits[wait] = true
+	}
+	desiredWaits := make(map[eosc.WaitWeight]bool, len(desired.Waits))
+	for _, wait := range desired.Waits {
+		desiredWaits[wait] = true
+		if !currentWaits[wait] {
+			diff.WaitsAdded = append(diff.WaitsAdded, wait)
+		}
+	}
+	for _, wait := range current.Waits {
+		if !desiredWaits[wait] {
+			diff.WaitsRemoved = append(diff.WaitsRemoved, wait)
+		}
+	}
+	return diff
+}
+
+func (m *AuthorityDiff) IsEmpty() bool {
+	return m.ThresholdFrom == m.ThresholdTo &&
+		len(m.KeysAdded) == 0 && len(m.KeysRemoved) == 0 &&
+		len(m.AccountsAdded) == 0 && len(m.AccountsRemoved) == 0 &&
+		len(m.WaitsAdded) == 0 && len(m.WaitsRemoved) == 0
+}
+
+func (m *AuthorityDiff) String() string {
+	if m.IsEmpty() {
+		return "no changes"
+	}
+	changes := make([]string, 0)
+	if m.ThresholdFrom != m.ThresholdTo {
+		changes = append(changes, fmt.Sprintf("threshold: %v -> %v", m.ThresholdFrom, m.ThresholdTo))
+	}
+	for _, key := range m.KeysRemoved {
+		changes = append(changes, fmt.Sprintf("- key: %v weight: %v", key.PublicKey, key.Weight))
+	}
+	for _, key := range m.KeysAdded {
+		changes = append(changes, fmt.Sprintf("+ key: %v weight: %v", key.PublicKey, key.Weight))
+	}
+	for _, account := range m.AccountsRemoved {
+		changes = append(changes, fmt.Sprintf("- account: %v@%v weight: %v", account.Permission.Actor, account.Permission.Permission, account.Weight))
+	}
+	for _, account := range m.AccountsAdded {
+		changes = append(changes, fmt.Sprintf("+ account: %v@%v weight: %v", account.Permission.Actor, account.Permission.Permission, account.Weight))
+	}
+	for _, wait := range m.WaitsRemoved {
+		changes = append(changes, fmt.Sprintf("- wait: %vs weight: %v", wait.WaitSec, wait.Weight))
+	}
+	for _, wait := range m.WaitsAdded {
+		changes = append(changes, fmt.Sprintf("+ wait: %vs weight: %v", wait.WaitSec, wait.Weight))
+	}
+	return strings.Join(changes, "\n")
+}
+
+func keyWeightID(key eosc.KeyWeight) string {
+	return fmt.Sprintf("%v:%v", key.PublicKey.String(), key.Weight)
+}
+
+// comparePublicKeys orders keys by curve and then by key data, as nodeos does
+func comparePublicKeys(a, b eosc.KeyWeight) int {
+	if a.PublicKey.Curve != b.PublicKey.Curve {
+		if a.PublicKey.Curve < b.PublicKey.Curve {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a.PublicKey.Content, b.PublicKey.Content)
+}
+
+// comparePermissionLevels orders by actor and then permission, for valid names comparing the strings
+// gives the same order as comparing their uint64 values, which is what nodeos does
+func comparePermissionLevels(a, b eosc.PermissionLevel) int {
+	if a.Actor != b.Actor {
+		return strings.Compare(string(a.Actor), string(b.Actor))
+	}
+	return strings.Compare(string(a.Permission), string(b.Permission))
+}
@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"gotest.tools/assert"
+)
+
+func newTestPublicKey(t *testing.T) ecc.PublicKey {
+	key, err := ecc.NewRandomPrivateKey()
+	assert.NilError(t, err)
+	return key.PublicKey()
+}
+
+func TestAuthorityBuilderSortsAndValidates(t *testing.T) {
+	keyA := newTestPublicKey(t)
+	keyB := newTestPublicKey(t)
+	authority, err := service.NewAuthorityBuilder(2).
+		Account("userb@active", 1).
+		Key(keyB, 1).
+		Account("usera@owner", 1).
+		Key(&keyA, 1).
+		Wait(3600, 1).
+		Account("usera@active", 1).
+		Build()
+	assert.NilError(t, err)
+	assert.Equal(t, len(authority.Keys), 2)
+	assert.NilError(t, service.ValidateAuthority(authority))
+	assert.DeepEqual(t, []eosc.PermissionLevel{
+		authority.Accounts[0].Permission,
+		authority.Accounts[1].Permission,
+		authority.Accounts[2].Permission,
+	}, []eosc.PermissionLevel{
+		{Actor: "usera", Permission: "active"},
+		{Actor: "usera", Permission: "owner"},
+		{Actor: "userb", Permission: "active"},
+	})
+
+	_, err = service.NewAuthorityBuilder(3).Key(keyA, 1).Account("usera@active", 1).Build()
+	assert.ErrorContains(t, err, "lower than the threshold")
+	_, err = service.NewAuthorityBuilder(1).Key(keyA, 1).Key(keyA, 2).Build()
+	assert.ErrorContains(t, err, "duplicated")
+	_, err = service.NewAuthorityBuilder(1).Account("usera@active", 0).Key(keyA, 1).Build()
+	assert.ErrorContains(t, err, "zero weight")
+	_, err = service.NewAuthorityBuilder(0).Key(keyA, 1).Build()
+	assert.ErrorContains(t, err, "threshold must be greater than zero")
+	_, err = service.NewAuthorityBuilder(1).Key(10, 1).Build()
+	assert.ErrorContains(t, err, "to PublicKey")
+}
+
+func TestDiffAuthority(t *testing.T) {
+	keyA := newTestPublicKey(t)
+	keyB := newTestPublicKey(t)
+	current, err := service.NewAuthorityBuilder(1).Key(keyA, 1).Account("usera@active", 1).Build()
+	assert.NilError(t, err)
+	same, err := service.NewAuthorityBuilder(1).Account("usera@active", 1).Key(keyA, 1).Build()
+	assert.NilError(t, err)
+	assert.Assert(t, service.DiffAuthority(current, same).IsEmpty())
+
+	desired, err := service.NewAuthorityBuilder(2).Key(keyA, 2).Key(keyB, 1).Account("usera@active", 1).Build()
+	assert.NilError(t, err)
+	diff := service.DiffAuthority(current, desired)
+	assert.Assert(t, !diff.IsEmpty())
+	assert.Equal(t, diff.ThresholdTo, uint32(2))
+	assert.Equal(t, len(diff.KeysAdded), 2)
+	assert.Equal(t, len(diff.KeysRemoved), 1)
+	assert.Equal(t, len(diff.AccountsAdded), 0)
+
+	diff = service.DiffAuthority(nil, current)
+	assert.Equal(t, len(diff.KeysAdded), 1)
+	assert.Equal(t, len(diff.AccountsAdded), 1)
+}
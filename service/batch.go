@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+)
+
+const (
+	defaultBatchMaxSize = 256 * 1024
+	// trxOverheadSize is an estimate of the size of the transaction header and a signature
+	trxOverheadSize = 128
+	// actionOverheadSize is an estimate of the size of the action envelope, account, name and one authorization
+	actionOverheadSize = 40
+)
+
+type BatchStatus string
+
+const (
+	BatchPending   BatchStatus = "pending"
+	BatchConfirmed BatchStatus = "confirmed"
+	BatchSkipped   BatchStatus = "skipped"
+	BatchFailed    BatchStatus = "failed"
+)
+
+type BatchOpts struct {
+	// MaxActions is the maximum number of actions per transaction, 0 means no limit
+	MaxActions int
+	// MaxSize is the maximum estimated serialized size in bytes of each transaction
+	MaxSize int
+	// CPUBudgetUS if set, each batch is dry run before being pushed and split in half
+	// while its estimated cpu usage is over the budget
+	CPUBudgetUS uint32
+	// Wait if set, waits for each batch to reach the specified finality before pushing the next one
+	Wait *WaitOpts
+	// StateFile if set, the report is saved to this file after each batch, and batches already
+	// confirmed in the file are skipped, so that an interrupted run can be resumed
+	StateFile string
+}
+
+func NewDefaultBatchOpts() *BatchOpts {
+	return &BatchOpts{
+		MaxSize: defaultBatchMaxSize,
+	}
+}
+
+type BatchResult struct {
+	Index int `json:"index"`
+	// Hash identifies the batch by its actions, it is used to skip confirmed batches when resuming
+	Hash          string      `json:"hash"`
+	Actions       int         `json:"actions"`
+	Size          int         `json:"size"`
+	CPUUsageUS    uint32      `json:"cpu_usage_us,omitempty"`
+	Status        BatchStatus `json:"status"`
+	TransactionID string      `json:"transaction_id,omitempty"`
+	BlockNum      uint32      `json:"block_num,omitempty"`
+	Error         string      `json:"error,omitempty"`
+}
+
+func (m *BatchResult) String() string {
+	return fmt.Sprintf("Batch: %v, Actions: %v, Size: %v, Status: %v, TransactionID: %v, Error: %v", m.Index, m.Actions, m.Size, m.Status, m.TransactionID, m.Error)
+}
+
+type BatchReport struct {
+	Batches []*BatchResult `json:"batches"`
+}
+
+// IsConfirmed returns true if the batch with the specified hash was confirmed, occurrence is the number
+// of batches with the same hash that come before it, so that batches with identical actions, such as
+// repeated transfers, are only skipped as many times as they were confirmed
+func (m *BatchReport) IsConfirmed(hash string, occurrence int) bool {
+	confirmed := 0
+	for _, batch := range m.Batches {
+		if batch.Hash == hash && (batch.Status == BatchConfirmed || batch.Status == BatchSkipped) {
+			confirmed++
+		}
+	}
+	return occurrence < confirmed
+}
+
+func (m *BatchReport) Count(status BatchStatus) int {
+	count := 0
+	for _, batch := range m.Batches {
+		if batch.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *BatchReport) String() string {
+	return fmt.Sprintf("Batches: %v, Confirmed: %v, Skipped: %v, Failed: %v", len(m.Batches), m.Count(BatchConfirmed), m.Count(BatchSkipped), m.Count(BatchFailed))
+}
+
+func (m *BatchReport) Save(path string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed marshalling batch report, error: %v", err)
+	}
+	// the report is the state used to resume, it is replaced atomically so that it is never left truncated
+	err = util.WriteFileAtomic(path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed writing batch report to: %v, error: %v", path, err)
+	}
+	return nil
+}
+
+// LoadBatchReport loads a report saved with Save, returns an empty report if the file does not exist
+func LoadBatchReport(path string) (*BatchReport, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &BatchReport{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading batch report from: %v, error: %v", path, err)
+	}
+	report := &BatchReport{}
+	err = json.Unmarshal(content, report)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling batch report from: %v, error: %v", path, err)
+	}
+	return report, nil
+}
+
+type actionBatch struct {
+	actions []*eosc.Action
+	size    int
+}
+
+// SplitActions splits the actions in batches that respect the max number of actions and max size
+// specified in the options, keeping the order of the actions
+func SplitActions(opts *BatchOpts, actions ...*eosc.Action) ([][]*eosc.Action, error) {
+	batches, err := splitActions(opts, actions)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]*eosc.Action, 0, len(batches))
+	for _, batch := range batches {
+		result = append(result, batch.actions)
+	}
+	return result, nil
+}
+
+func splitActions(opts *BatchOpts, actions []*eosc.Action) ([]*actionBatch, error) {
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultBatchMaxSize
+	}
+	batches := make([]*actionBatch, 0)
+	current := &actionBatch{size: trxOverheadSize}
+	for i, action := range actions {
+		size, err := estimateActionSize(action)
+		if err != nil {
+			return nil, fmt.Errorf("failed estimating size of action: %v, error: %v", i, err)
+		}
+		if size+trxOverheadSize > maxSize {
+			return nil, fmt.Errorf("action: %v, of estimated size: %v does not fit in a transaction of max size: %v", i, size, maxSize)
+		}
+		full := opts.MaxActions > 0 && len(current.actions) >= opts.MaxActions
+		if len(current.actions) > 0 && (full || current.size+size > maxSize) {
+			batches = append(batches, current)
+			current = &actionBatch{size: trxOverheadSize}
+		}
+		current.actions = append(current.actions, action)
+		current.size += size
+	}
+	if len(current.actions) > 0 {
+		batches = append(batches, current)
+	}
+	return batches, nil
+}
+
+func estimateActionSize(action *eosc.Action) (int, error) {
+	packed, err := eosc.MarshalBinary(action)
+	if err == nil {
+		return len(packed), nil
+	}
+	// data that can only be encoded by the node using the abi, the json size is an overestimate
+	content, err := json.Marshal(action)
+	if err != nil {
+		return 0, err
+	}
+	return len(content) + actionOverheadSize, nil
+}
+
+func hashActions(actions []*eosc.Action) (string, error) {
+	content, err := json.Marshal(actions)
+	if err != nil {
+		return "", fmt.Errorf("failed hashing actions, error: %v", err)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// BatchTrx pushes the actions in as many transactions as required to respect the limits
+// specified in the options, the transactions are pushed in order and it stops at the first
+// one that fails, the report has the result of every batch
+func (m *EOS) BatchTrx(opts *BatchOpts, actions ...*eosc.Action) (*BatchReport, error) {
+	return m.BatchTrxCtx(context.Background(), opts, actions...)
+}
+
+func (m *EOS) BatchTrxCtx(ctx context.Context, opts *BatchOpts, actions ...*eosc.Action) (*BatchReport, error) {
+	if opts == nil {
+		opts = NewDefaultBatchOpts()
+	}
+	previous := &BatchReport{}
+	if opts.StateFile != "" {
+		var err error
+		previous, err = LoadBatchReport(opts.StateFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	batches, err := splitActions(opts, actions)
+	if err != nil {
+		return nil, fmt.Errorf("failed splitting actions into batches, error: %v", err)
+	}
+	report := &BatchReport{
+		Batches: make([]*BatchResult, 0, len(batches)),
+	}
+	occurrences := make(map[string]int)
+	for len(batches) > 0 {
+		batch := batches[0]
+		batches = batches[1:]
+		hash, err := hashActions(batch.actions)
+		if err != nil {
+			return report, err
+		}
+		result := &BatchResult{
+			Index:   len(report.Batches),
+			Hash:    hash,
+			Actions: len(batch.actions),
+			Size:    batch.size,
+			Status:  BatchPending,
+		}
+		if previous.IsConfirmed(hash, occurrences[hash]) {
+			occurrences[hash]++
+			result.Status = BatchSkipped
+			report.Batches = append(report.Batches, result)
+			continue
+		}
+		if opts.CPUBudgetUS > 0 {
+			halves, cpuUsage, err := m.checkCPUBudget(ctx, opts.CPUBudgetUS, batch)
+			if err != nil {
+				return report, err
+			}
+			if halves != nil {
+				batches = append(halves, batches...)
+				continue
+			}
+			result.CPUUsageUS = cpuUsage
+		}
+		occurrences[hash]++
+		report.Batches = append(report.Batches, result)
+		err = m.pushBatch(ctx, opts, batch, result)
+		if opts.StateFile != "" {
+			if saveErr := report.Save(opts.StateFile); saveErr != nil && err == nil {
+				err = saveErr
+			}
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed pushing batch: %v, error: %w", result.Index, err)
+		}
+	}
+	return report, nil
+}
+
+// checkCPUBudget dry runs the batch, if it is over the cpu budget the batch is returned split in half
+func (m *EOS) checkCPUBudget(ctx context.Context, budget uint32, batch *actionBatch) ([]*actionBatch, uint32, error) {
+	dryRun, err := m.DryRunCtx(ctx, batch.actions...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed estimating batch cpu usage, error: %v", err)
+	}
+	overBudget := dryRun.CPUUsageUS > budget || (dryRun.Error != nil && dryRun.Error.IsResourceExhausted())
+	if !overBudget || len(batch.actions) == 1 {
+		// other dry run errors are reported when the batch is pushed
+		return nil, dryRun.CPUUsageUS, nil
+	}
+	middle := len(batch.actions) / 2
+	halves := make([]*actionBatch, 0, 2)
+	for _, actions := range [][]*eosc.Action{batch.actions[:middle], batch.actions[middle:]} {
+		half := &actionBatch{
+			actions: actions,
+			size:    trxOverheadSize,
+		}
+		for _, action := range actions {
+			size, err := estimateActionSize(action)
+			if err != nil {
+				return nil, 0, err
+			}
+			half.size += size
+		}
+		halves = append(halves, half)
+	}
+	return halves, 0, nil
+}
+
+func (m *EOS) pushBatch(ctx context.Context, opts *BatchOpts, batch *actionBatch, result *BatchResult) error {
+	var err error
+	if opts.Wait != nil {
+		var receipt *TrxReceipt
+		receipt, err = m.TrxAndWaitCtx(ctx, opts.Wait, batch.actions...)
+		if receipt != nil {
+			result.TransactionID = receipt.TransactionID
+			result.BlockNum = receipt.BlockNum
+		}
+	} else {
+		var resp *eosc.PushTransactionFullResp
+		resp, err = m.TrxCtx(ctx, batch.actions...)
+		if resp != nil {
+			result.TransactionID = resp.TransactionID
+			result.BlockNum = resp.BlockNum
+		}
+	}
+	if err != nil && !eoserr.IsDuplicate(err) {
+		result.Status = BatchFailed
+		result.Error = err.Error()
+		return err
+	}
+	result.Status = BatchConfirmed
+	return nil
+}
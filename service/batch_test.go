@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go/system"
+	"gotest.tools/assert"
+)
+
+func newAccountActions(names ...string) []*eosc.Action {
+	actions := make([]*eosc.Action, 0, len(names))
+	for _, name := range names {
+		actions = append(actions, system.NewNewAccount("eosio", eosc.AN(name), *service.GetEOSIOPublicKey()))
+	}
+	return actions
+}
+
+func TestSplitActionsByCount(t *testing.T) {
+	actions := newAccountActions("batcha", "batchb", "batchc", "batchd", "batche")
+	batches, err := service.SplitActions(&service.BatchOpts{MaxActions: 2}, actions...)
+	assert.NilError(t, err)
+	assert.Equal(t, len(batches), 3)
+	assert.Equal(t, len(batches[0]), 2)
+	assert.Equal(t, len(batches[2]), 1)
+	assert.Equal(t, batches[2][0], actions[4])
+}
+
+func TestSplitActionsActionTooBig(t *testing.T) {
+	_, err := service.SplitActions(&service.BatchOpts{MaxSize: 100}, newAccountActions("batcha")...)
+	assert.ErrorContains(t, err, "does not fit in a transaction")
+}
+
+func TestBatchReportSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	report, err := service.LoadBatchReport(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Batches), 0)
+	report.Batches = append(report.Batches,
+		&service.BatchResult{Index: 0, Hash: "a", Status: service.BatchConfirmed},
+		&service.BatchResult{Index: 1, Hash: "b", Status: service.BatchFailed},
+	)
+	assert.NilError(t, report.Save(path))
+	loaded, err := service.LoadBatchReport(path)
+	assert.NilError(t, err)
+	assert.Assert(t, loaded.IsConfirmed("a", 0))
+	assert.Assert(t, !loaded.IsConfirmed("b", 0))
+	assert.Equal(t, loaded.Count(service.BatchFailed), 1)
+}
+
+func TestBatchReportIsConfirmedRepeatedBatches(t *testing.T) {
+	report := &service.BatchReport{
+		Batches: []*service.BatchResult{
+			{Index: 0, Hash: "transfer", Status: service.BatchConfirmed},
+			{Index: 1, Hash: "issue", Status: service.BatchConfirmed},
+			{Index: 2, Hash: "transfer", Status: service.BatchFailed},
+		},
+	}
+	assert.Assert(t, report.IsConfirmed("transfer", 0))
+	assert.Assert(t, !report.IsConfirmed("transfer", 1))
+	assert.Assert(t, report.IsConfirmed("issue", 0))
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"github.com/sebastianmontero/eos-go/system"
+)
+
+type DelegateBWOpts struct {
+	NetStake eosc.Asset
+	CPUStake eosc.Asset
+	// Transfer gives the staked tokens to the new account instead of only delegating them
+	Transfer bool
+}
+
+// CreateAccountOpts configures the actions pushed in the same transaction as newaccount
+type CreateAccountOpts struct {
+	// Creator pays for the account and its resources, defaults to eosio
+	Creator interface{}
+	// CreatorPermission authorizes the actions, defaults to active
+	CreatorPermission interface{}
+	Owner             *eosc.Authority
+	// Active defaults to the Owner authority
+	Active *eosc.Authority
+	// RAMBytes if greater than zero are bought with buyrambytes
+	RAMBytes uint32
+	// DelegateBW if set stakes NET and CPU for the new account
+	DelegateBW *DelegateBWOpts
+	// Powerup if set rents NET and CPU for the new account, Payer and Receiver default to the
+	// creator and the new account
+	Powerup *PowerupArgs
+	// FailIfExists returns an error if the account already exists, otherwise the account is
+	// left as it is and no error is returned
+	FailIfExists bool
+}
+
+// NewCreateAccountOpts returns the options to create an account with the public key as the only key of
+// the owner and active permissions, that is what CreateAccount does
+func NewCreateAccountOpts(publicKey *ecc.PublicKey) *CreateAccountOpts {
+	return &CreateAccountOpts{
+		Owner: &eosc.Authority{
+			Threshold: 1,
+			Keys:      []eosc.KeyWeight{{PublicKey: *publicKey, Weight: 1}},
+			Accounts:  []eosc.PermissionLevelWeight{},
+			Waits:     []eosc.WaitWeight{},
+		},
+	}
+}
+
+// CreateAccountWithOpts creates the account and buys or rents its resources in the same transaction
+func (m *EOS) CreateAccountWithOpts(accountName interface{}, opts *CreateAccountOpts) (eosc.AccountName, error) {
+	return m.CreateAccountWithOptsCtx(context.Background(), accountName, opts)
+}
+
+func (m *EOS) CreateAccountWithOptsCtx(ctx context.Context, accountName interface{}, opts *CreateAccountOpts) (eosc.AccountName, error) {
+	account, err := util.ToAccountName(accountName)
+	if err != nil {
+		return "", err
+	}
+	if !opts.FailIfExists {
+		accountData, err := m.FindAccountCtx(ctx, account)
+		if err != nil {
+			return "", err
+		}
+		if accountData != nil {
+			return account, nil
+		}
+	}
+	actions, err := m.GetCreateAccountActions(account, opts)
+	if err != nil {
+		return "", err
+	}
+	_, err = m.TrxCtx(ctx, actions...)
+	if err != nil {
+		if opts.FailIfExists || !eoserr.IsAccountExists(err) {
+			return "", fmt.Errorf("error creating account: %v, error: %w", account, err)
+		}
+	}
+	return account, nil
+}
+
+// GetCreateAccountActions returns the newaccount action followed by the actions to get its resources
+func (m *EOS) GetCreateAccountActions(accountName interface{}, opts *CreateAccountOpts) ([]*eosc.Action, error) {
+	account, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	creator := eosc.AccountName("eosio")
+	if opts.Creator != nil {
+		creator, err = util.ToAccountName(opts.Creator)
+		if err != nil {
+			return nil, err
+		}
+	}
+	creatorPermission := eosc.PermissionName("active")
+	if opts.CreatorPermission != nil {
+		creatorPermission, err = util.ToPermissionName(opts.CreatorPermission)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.Owner == nil {
+		return nil, fmt.Errorf("failed creating account: %v, the owner authority is required", account)
+	}
+	owner := CopyAuthority(opts.Owner)
+	active := owner
+	if opts.Active != nil {
+		active = CopyAuthority(opts.Active)
+	}
+	for name, authority := range map[string]*eosc.Authority{"owner": owner, "active": active} {
+		SortAuthority(authority)
+		if err := ValidateAuthority(authority); err != nil {
+			return nil, fmt.Errorf("failed creating account: %v, invalid %v authority, error: %v", account, name, err)
+		}
+	}
+	actions := []*eosc.Action{system.NewCustomNewAccount(creator, account, *owner, *active)}
+	if opts.RAMBytes > 0 {
+		actions = append(actions, system.NewBuyRAMBytes(creator, account, opts.RAMBytes))
+	}
+	if opts.DelegateBW != nil {
+		actions = append(actions, system.NewDelegateBW(creator, account, opts.DelegateBW.CPUStake, opts.DelegateBW.NetStake, opts.DelegateBW.Transfer))
+	}
+	if opts.Powerup != nil {
+		payer, receiver := opts.Powerup.Payer, opts.Powerup.Receiver
+		if payer == "" {
+			payer = creator
+		}
+		if receiver == "" {
+			receiver = account
+		}
+		action, err := m.BuildPowerupAction(payer, receiver, opts.Powerup.Days, opts.Powerup.NetFrac, opts.Powerup.CPUFrac, opts.Powerup.MaxPayment)
+		if err != nil {
+			return nil, fmt.Errorf("failed building powerup action for account: %v, error: %v", account, err)
+		}
+		actions = append(actions, action)
+	}
+	authorization := []eosc.PermissionLevel{{Actor: creator, Permission: creatorPermission}}
+	for _, action := range actions {
+		if len(action.Authorization) == 1 && action.Authorization[0].Actor == creator {
+			action.Authorization = authorization
+		}
+	}
+	return actions, nil
+}
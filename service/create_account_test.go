@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func TestGetCreateAccountActions(t *testing.T) {
+	api, err := eosc.New("http://localhost:8888")
+	assert.NilError(t, err)
+	eos := service.NewEOS(api)
+	ownerKey := newTestPublicKey(t)
+	active, err := service.NewAuthorityBuilder(1).Key(newTestPublicKey(t), 1).Account("usera@active", 1).Build()
+	assert.NilError(t, err)
+	opts := service.NewCreateAccountOpts(&ownerKey)
+	opts.Creator = "usera"
+	opts.CreatorPermission = "create"
+	opts.Active = active
+	opts.RAMBytes = 4096
+	opts.DelegateBW = &service.DelegateBWOpts{
+		NetStake: eosAsset(10000),
+		CPUStake: eosAsset(10000),
+	}
+	opts.Powerup = &service.PowerupArgs{
+		Days:       1,
+		NetFrac:    service.PowerupFracScale / 1000,
+		CPUFrac:    service.PowerupFracScale / 1000,
+		MaxPayment: eosAsset(10000),
+	}
+	actions, err := eos.GetCreateAccountActions("newaccount", opts)
+	assert.NilError(t, err)
+	names := make([]eosc.ActionName, 0, len(actions))
+	for _, action := range actions {
+		names = append(names, action.Name)
+		assert.DeepEqual(t, action.Authorization, []eosc.PermissionLevel{{Actor: "usera", Permission: "create"}})
+	}
+	assert.DeepEqual(t, names, []eosc.ActionName{"newaccount", "buyrambytes", "delegatebw", "powerup"})
+
+	opts.Active = &eosc.Authority{Threshold: 2, Keys: []eosc.KeyWeight{{PublicKey: ownerKey, Weight: 1}}}
+	_, err = eos.GetCreateAccountActions("newaccount", opts)
+	assert.ErrorContains(t, err, "invalid active authority")
+
+	_, err = eos.GetCreateAccountActions("newaccount", &service.CreateAccountOpts{})
+	assert.ErrorContains(t, err, "owner authority is required")
+}
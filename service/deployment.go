@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"github.com/sebastianmontero/eos-go/system"
+)
+
+// DeploymentReport states what was updated by SetContract
+type DeploymentReport struct {
+	Account eosc.AccountName `json:"account"`
+	// CodeHash is the hash of the local wasm
+	CodeHash string `json:"code_hash"`
+	// PreviousCodeHash is the hash of the code on chain before the deployment
+	PreviousCodeHash string `json:"previous_code_hash"`
+	CodeUpdated      bool   `json:"code_updated"`
+	ABIUpdated       bool   `json:"abi_updated"`
+	// ABIDiff has the changes of the ABI, nil if it was not updated
+	ABIDiff *ABIDiff `json:"abi_diff,omitempty"`
+	// TransactionID is empty if nothing was updated
+	TransactionID string `json:"transaction_id,omitempty"`
+	// Response is nil if nothing was updated
+	Response *eosc.PushTransactionFullResp `json:"-"`
+}
+
+// Updated returns true if the code or the ABI were pushed
+func (m *DeploymentReport) Updated() bool {
+	return m.CodeUpdated || m.ABIUpdated
+}
+
+// BreakingABIError returns a BreakingABIError if the new ABI has breaking changes, nil otherwise
+func (m *DeploymentReport) BreakingABIError() error {
+	if m.ABIDiff == nil {
+		return nil
+	}
+	return m.ABIDiff.BreakingABIError(m.Account)
+}
+
+func (m *DeploymentReport) String() string {
+	updated := make([]string, 0, 2)
+	if m.CodeUpdated {
+		updated = append(updated, "code")
+	}
+	if m.ABIUpdated {
+		updated = append(updated, "abi")
+	}
+	if len(updated) == 0 {
+		return fmt.Sprintf("contract of account: %v is up to date, code hash: %v", m.Account, m.CodeHash)
+	}
+	return fmt.Sprintf("updated %v of account: %v, code hash: %v -> %v, transaction: %v",
+		strings.Join(updated, " and "), m.Account, m.PreviousCodeHash, m.CodeHash, m.TransactionID)
+}
+
+func (m *EOS) GetDeployContractActions(accountName interface{}, wasmFile, abiFile string) ([]*eosc.Action, *DeploymentReport, error) {
+	return m.GetDeployContractActionsCtx(context.Background(), accountName, wasmFile, abiFile)
+}
+
+// GetDeployContractActionsCtx returns the setcode and setabi actions of the code and ABI that differ from the
+// ones on chain, the code is compared by hash and the ABI in its binary form, the actions are empty if both match
+func (m *EOS) GetDeployContractActionsCtx(ctx context.Context, accountName interface{}, wasmFile, abiFile string) ([]*eosc.Action, *DeploymentReport, error) {
+	account, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, nil, err
+	}
+	code, err := ioutil.ReadFile(wasmFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed reading wasm file: %v, error: %v", wasmFile, err)
+	}
+	codeHash := sha256.Sum256(code)
+	report := &DeploymentReport{
+		Account:  account,
+		CodeHash: hex.EncodeToString(codeHash[:]),
+	}
+	localABI, err := LoadABIFile(abiFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	packedLocalABI, err := eosc.MarshalBinary(localABI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed packing abi file: %v, error: %v", abiFile, err)
+	}
+	var currentCodeHash eosc.Checksum256
+	err = m.withRetries(ctx, func() (err error) {
+		currentCodeHash, err = m.API.GetCodeHash(ctx, account)
+		return
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed getting code hash of account: %v, error: %w", account, err)
+	}
+	report.PreviousCodeHash = hex.EncodeToString(currentCodeHash)
+	currentABI, err := m.GetABICtx(ctx, account)
+	if err != nil {
+		return nil, nil, err
+	}
+	var packedCurrentABI []byte
+	if currentABI != nil {
+		packedCurrentABI, err = eosc.MarshalBinary(currentABI)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed packing abi of account: %v, error: %v", account, err)
+		}
+	}
+	actions := make([]*eosc.Action, 0, 2)
+	if report.PreviousCodeHash != report.CodeHash {
+		setCodeAction, err := system.NewSetCode(account, wasmFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable construct set_code action: %v", err)
+		}
+		actions = append(actions, setCodeAction)
+		report.CodeUpdated = true
+	}
+	if currentABI == nil || !bytes.Equal(packedCurrentABI, packedLocalABI) {
+		setAbiAction, err := system.NewSetABI(account, abiFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable construct set_abi action: %v", err)
+		}
+		actions = append(actions, setAbiAction)
+		report.ABIUpdated = true
+		report.ABIDiff = DiffABI(currentABI, localABI)
+	}
+	return actions, report, nil
+}
+
+type SetContractOpts struct {
+	// PublicKey if not nil is used to create the account if it does not exist
+	PublicKey *ecc.PublicKey
+	// AllowBreakingABI deploys the contract even if the new ABI has breaking changes
+	AllowBreakingABI bool
+}
+
+// SetContractWithOpts sets the contract like SetContract, but fails with a BreakingABIError without pushing
+// anything if the new ABI has breaking changes, unless AllowBreakingABI is set, nil opts are the same as the
+// zero value
+func (m *EOS) SetContractWithOpts(accountName interface{}, wasmFile, abiFile string, opts *SetContractOpts) (*DeploymentReport, error) {
+	return m.SetContractWithOptsCtx(context.Background(), accountName, wasmFile, abiFile, opts)
+}
+
+func (m *EOS) SetContractWithOptsCtx(ctx context.Context, accountName interface{}, wasmFile, abiFile string, opts *SetContractOpts) (*DeploymentReport, error) {
+	if opts == nil {
+		opts = &SetContractOpts{}
+	}
+	if opts.PublicKey != nil {
+		existing, err := m.FindAccountCtx(ctx, accountName)
+		if err != nil {
+			return nil, err
+		}
+		// the account is only created if it does not exist, it has no ABI so there can not be breaking changes
+		if existing == nil {
+			_, err := m.CreateAccountCtx(ctx, accountName, opts.PublicKey, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	actions, report, err := m.GetDeployContractActionsCtx(ctx, accountName, wasmFile, abiFile)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.AllowBreakingABI {
+		if err := report.BreakingABIError(); err != nil {
+			return nil, err
+		}
+	}
+	if len(actions) == 0 {
+		return report, nil
+	}
+	resp, err := m.TrxCtx(ctx, actions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed deploying contract to account: %v, error: %w", report.Account, err)
+	}
+	report.TransactionID = resp.TransactionID
+	report.Response = resp
+	return report, nil
+}
@@ -0,0 +1,35 @@
+package service_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func TestDeploymentReport(t *testing.T) {
+	report := &service.DeploymentReport{
+		Account:          "contracta",
+		CodeHash:         "bb",
+		PreviousCodeHash: "aa",
+	}
+	assert.Assert(t, !report.Updated())
+	assert.Equal(t, report.String(), "contract of account: contracta is up to date, code hash: bb")
+
+	report.CodeUpdated = true
+	report.ABIUpdated = true
+	report.TransactionID = "trxid"
+	assert.Assert(t, report.Updated())
+	assert.Equal(t, report.String(), "updated code and abi of account: contracta, code hash: aa -> bb, transaction: trxid")
+}
+
+func TestGetDeployContractActionsMissingFiles(t *testing.T) {
+	api, err := eosc.New("http://localhost:8888")
+	assert.NilError(t, err)
+	eos := service.NewEOS(api)
+	dir := t.TempDir()
+	_, _, err = eos.GetDeployContractActions("contracta", filepath.Join(dir, "contract.wasm"), filepath.Join(dir, "contract.abi"))
+	assert.ErrorContains(t, err, "failed reading wasm file")
+}
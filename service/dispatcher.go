@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+)
+
+const defaultDispatcherWorkers = 4
+const defaultDispatcherQueueSize = 100
+
+var ErrDispatcherClosed = errors.New("dispatcher is closed")
+
+type DispatcherOpts struct {
+	Workers int
+	// TPS limits the transactions per second pushed by all the workers, 0 means no limit
+	TPS float64
+	// QueueSize is the number of transactions each worker can have queued, the results buffer
+	// holds the queued transactions of all the workers, so Close does not block if the dispatcher
+	// was never started
+	QueueSize int
+}
+
+func NewDefaultDispatcherOpts() *DispatcherOpts {
+	return &DispatcherOpts{
+		Workers:   defaultDispatcherWorkers,
+		QueueSize: defaultDispatcherQueueSize,
+	}
+}
+
+// DispatchResult reports the outcome of a transaction submitted to the dispatcher
+type DispatchResult struct {
+	// Seq is the sequence number returned by Submit
+	Seq           uint64
+	Actions       []*eosc.Action
+	TransactionID string
+	Resp          *eosc.PushTransactionFullResp
+	Error         error
+}
+
+func (m *DispatchResult) String() string {
+	if m.Error != nil {
+		return fmt.Sprintf("Seq: %v, Error: %v", m.Seq, m.Error)
+	}
+	return fmt.Sprintf("Seq: %v, TransactionID: %v", m.Seq, m.TransactionID)
+}
+
+type dispatchJob struct {
+	seq     uint64
+	actions []*eosc.Action
+}
+
+// Dispatcher pushes transactions concurrently using a pool of workers, transactions are
+// assigned to workers by the actor of their first authorization, so transactions of the same
+// actor are pushed in the order they were submitted. The results must be consumed from the
+// Results channel, otherwise workers block once the results buffer is full
+type Dispatcher struct {
+	// seq is first to keep it 64 bit aligned for atomic operations
+	seq     uint64
+	eos     *EOS
+	opts    *DispatcherOpts
+	queues  []chan *dispatchJob
+	results chan *DispatchResult
+	limiter *time.Ticker
+	wg      sync.WaitGroup
+	// mutex guards closed, Submit does not hold it while waiting on a full queue, done unblocks the
+	// waiting submits on Close, and the queues are closed once the in flight submits have returned
+	mutex    sync.Mutex
+	closed   bool
+	done     chan struct{}
+	inFlight sync.WaitGroup
+	// startMutex is separate so that Start is not blocked by a Submit waiting on a full queue
+	startMutex sync.Mutex
+	started    bool
+}
+
+func NewDispatcher(eos *EOS, opts *DispatcherOpts) *Dispatcher {
+	if opts == nil {
+		opts = NewDefaultDispatcherOpts()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultDispatcherWorkers
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultDispatcherQueueSize
+	}
+	queues := make([]chan *dispatchJob, workers)
+	for i := range queues {
+		queues[i] = make(chan *dispatchJob, queueSize)
+	}
+	var limiter *time.Ticker
+	if opts.TPS > 0 {
+		limiter = time.NewTicker(time.Duration(float64(time.Second) / opts.TPS))
+	}
+	return &Dispatcher{
+		eos:     eos,
+		opts:    opts,
+		queues:  queues,
+		results: make(chan *DispatchResult, workers*queueSize),
+		limiter: limiter,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start launches the workers, they stop when the context is done or the dispatcher is closed,
+// transactions that were not pushed when the context is done are reported with the context error
+func (m *Dispatcher) Start(ctx context.Context) {
+	m.startMutex.Lock()
+	defer m.startMutex.Unlock()
+	if m.started {
+		return
+	}
+	m.started = true
+	m.eos.ensureSigner()
+	for _, queue := range m.queues {
+		m.wg.Add(1)
+		go m.work(ctx, queue)
+	}
+}
+
+// Results returns the channel on which the result of every submitted transaction is reported,
+// it is closed after Close is called and all the pending transactions are processed
+func (m *Dispatcher) Results() <-chan *DispatchResult {
+	return m.results
+}
+
+// Submit queues the actions to be pushed as one transaction and returns its sequence number,
+// it blocks if the worker queue is full until there is room, the context is done or the dispatcher
+// is closed, it is safe to call from multiple goroutines
+func (m *Dispatcher) Submit(ctx context.Context, actions ...*eosc.Action) (uint64, error) {
+	if len(actions) == 0 {
+		return 0, fmt.Errorf("failed submitting transaction, at least one action is required")
+	}
+	m.mutex.Lock()
+	if m.closed {
+		m.mutex.Unlock()
+		return 0, ErrDispatcherClosed
+	}
+	m.inFlight.Add(1)
+	m.mutex.Unlock()
+	defer m.inFlight.Done()
+	job := &dispatchJob{
+		seq:     atomic.AddUint64(&m.seq, 1),
+		actions: actions,
+	}
+	select {
+	case m.queues[m.queueIndex(actions)] <- job:
+		return job.seq, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-m.done:
+		return 0, ErrDispatcherClosed
+	}
+}
+
+// Close stops accepting transactions and waits until the queued ones are processed, submits blocked
+// on a full queue fail with ErrDispatcherClosed, if the dispatcher was never started the queued
+// transactions are reported with ErrDispatcherClosed
+func (m *Dispatcher) Close() {
+	m.mutex.Lock()
+	if m.closed {
+		m.mutex.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.done)
+	m.mutex.Unlock()
+	m.inFlight.Wait()
+	for _, queue := range m.queues {
+		close(queue)
+	}
+
+	m.startMutex.Lock()
+	started := m.started
+	// prevents the workers from being started after closing
+	m.started = true
+	m.startMutex.Unlock()
+	if started {
+		m.wg.Wait()
+	} else {
+		for _, queue := range m.queues {
+			for job := range queue {
+				m.results <- m.newResult(job, nil, ErrDispatcherClosed)
+			}
+		}
+	}
+	if m.limiter != nil {
+		m.limiter.Stop()
+	}
+	close(m.results)
+}
+
+func (m *Dispatcher) queueIndex(actions []*eosc.Action) int {
+	var actor string
+	if len(actions[0].Authorization) > 0 {
+		actor = string(actions[0].Authorization[0].Actor)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(actor))
+	return int(h.Sum32() % uint32(len(m.queues)))
+}
+
+func (m *Dispatcher) work(ctx context.Context, queue chan *dispatchJob) {
+	defer m.wg.Done()
+	for job := range queue {
+		if err := m.wait(ctx); err != nil {
+			m.results <- m.newResult(job, nil, err)
+			continue
+		}
+		resp, err := m.eos.TrxCtx(ctx, job.actions...)
+		m.results <- m.newResult(job, resp, err)
+	}
+}
+
+// wait blocks until the rate limit allows another transaction to be pushed
+func (m *Dispatcher) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if m.limiter == nil {
+		return nil
+	}
+	select {
+	case <-m.limiter.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Dispatcher) newResult(job *dispatchJob, resp *eosc.PushTransactionFullResp, err error) *DispatchResult {
+	result := &DispatchResult{
+		Seq:     job.seq,
+		Actions: job.actions,
+		Resp:    resp,
+		Error:   err,
+	}
+	if resp != nil {
+		result.TransactionID = resp.TransactionID
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go/system"
+	"gotest.tools/assert"
+)
+
+func TestDispatcherCloseWithFullQueue(t *testing.T) {
+	dispatcher := service.NewDispatcher(&service.EOS{}, &service.DispatcherOpts{
+		Workers:   1,
+		QueueSize: 2,
+	})
+	newAccount := func(account string) *eosc.Action {
+		return system.NewNewAccount("eosio", eosc.AN(account), *service.GetEOSIOPublicKey())
+	}
+	for _, account := range []string{"fulla", "fullb"} {
+		_, err := dispatcher.Submit(context.Background(), newAccount(account))
+		assert.NilError(t, err)
+	}
+	blocked := make(chan error)
+	go func() {
+		_, err := dispatcher.Submit(context.Background(), newAccount("fullc"))
+		blocked <- err
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		dispatcher.Close()
+		close(closed)
+	}()
+	select {
+	case err := <-blocked:
+		assert.Equal(t, err, service.ErrDispatcherClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("submit blocked on a full queue was not released by close")
+	}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close did not return")
+	}
+	results := 0
+	for result := range dispatcher.Results() {
+		assert.Equal(t, result.Error, service.ErrDispatcherClosed)
+		results++
+	}
+	assert.Equal(t, results, 2)
+}
+
+func TestDispatcherCloseNeverStarted(t *testing.T) {
+	dispatcher := service.NewDispatcher(&service.EOS{}, &service.DispatcherOpts{
+		Workers:   3,
+		QueueSize: 2,
+	})
+	// actors are spread among the workers by hash, submit until every queue is full
+	submitted := 0
+	for i := 0; submitted < 6 && i < 1000; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		action := system.NewNewAccount(eosc.AN(fmt.Sprintf("actor%v", i)), "newaccount", *service.GetEOSIOPublicKey())
+		_, err := dispatcher.Submit(ctx, action)
+		cancel()
+		if err == nil {
+			submitted++
+		}
+	}
+	assert.Equal(t, submitted, 6)
+
+	closed := make(chan struct{})
+	go func() {
+		dispatcher.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close blocked reporting the queued transactions")
+	}
+	results := 0
+	for result := range dispatcher.Results() {
+		assert.Equal(t, result.Error, service.ErrDispatcherClosed)
+		results++
+	}
+	assert.Equal(t, results, 6)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/dto"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+)
+
+type dryRunResp struct {
+	TransactionID string                `json:"transaction_id"`
+	Processed     *dto.TransactionTrace `json:"processed"`
+}
+
+// DryRunResult reports the outcome of executing a transaction without committing it
+type DryRunResult struct {
+	TransactionID string
+	Trace         *dto.TransactionTrace
+	// CPUUsageUS and NetUsageWords are the estimated resources the transaction would be billed
+	CPUUsageUS    uint32
+	NetUsageWords uint32
+	Console       string
+	// Error is set when the transaction would fail
+	Error *eoserr.ChainError
+}
+
+func (m *DryRunResult) Succeeded() bool {
+	return m.Error == nil
+}
+
+// AssertMessage returns the message of the eosio_assert that would fail, empty if there is none
+func (m *DryRunResult) AssertMessage() string {
+	if m.Error == nil {
+		return ""
+	}
+	return m.Error.AssertMessage()
+}
+
+func (m *DryRunResult) String() string {
+	if m.Error != nil {
+		return fmt.Sprintf("TransactionID: %v, Failed: %v", m.TransactionID, m.Error)
+	}
+	return fmt.Sprintf("TransactionID: %v, CPU: %vus, NET: %v words", m.TransactionID, m.CPUUsageUS, m.NetUsageWords)
+}
+
+// DryRun executes the actions using compute_transaction, which runs the transaction
+// without committing it and does not require it to be signed
+func (m *EOS) DryRun(actions ...*eosc.Action) (*DryRunResult, error) {
+	return m.DryRunCtx(context.Background(), actions...)
+}
+
+func (m *EOS) DryRunCtx(ctx context.Context, actions ...*eosc.Action) (*DryRunResult, error) {
+	return m.dryRun(ctx, "compute_transaction", actions...)
+}
+
+// ReadOnlyTrx executes the actions as a read only transaction, the actions must not modify state,
+// it is useful to call actions that return values
+func (m *EOS) ReadOnlyTrx(actions ...*eosc.Action) (*DryRunResult, error) {
+	return m.ReadOnlyTrxCtx(context.Background(), actions...)
+}
+
+func (m *EOS) ReadOnlyTrxCtx(ctx context.Context, actions ...*eosc.Action) (*DryRunResult, error) {
+	return m.dryRun(ctx, "send_read_only_transaction", actions...)
+}
+
+func (m *EOS) dryRun(ctx context.Context, endpoint string, actions ...*eosc.Action) (*DryRunResult, error) {
+	tx, err := m.BuildTrxCtx(ctx, 0, actions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed building transaction to dry run, error: %v", err)
+	}
+	packedTx, err := eosc.NewSignedTransaction(tx).Pack(eosc.CompressionNone)
+	if err != nil {
+		return nil, fmt.Errorf("failed packing transaction to dry run, error: %v", err)
+	}
+	var resp dryRunResp
+	err = m.withRetries(ctx, func() error {
+		return m.API.Call(ctx, "chain", endpoint, M{"transaction": packedTx}, &resp)
+	})
+	if err != nil {
+		if chainErr, ok := eoserr.AsChainError(err); ok {
+			return &DryRunResult{
+				Error: chainErr,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed calling %v, error: %w", endpoint, err)
+	}
+	result := &DryRunResult{
+		TransactionID: resp.TransactionID,
+		Trace:         resp.Processed,
+	}
+	if resp.Processed != nil {
+		result.Console = resp.Processed.Console()
+		if resp.Processed.Receipt != nil {
+			result.CPUUsageUS = resp.Processed.Receipt.CPUUsageUS
+			result.NetUsageWords = resp.Processed.Receipt.NetUsageWords
+		}
+		if except := resp.Processed.FindException(); except != nil {
+			result.Error = exceptionToChainError(except)
+		}
+	}
+	return result, nil
+}
+
+func exceptionToChainError(except *dto.Exception) *eoserr.ChainError {
+	details := make([]*eoserr.ChainErrorDetail, 0, len(except.Stack))
+	for _, stack := range except.Stack {
+		detail := &eoserr.ChainErrorDetail{
+			Message: stack.Message(),
+		}
+		if stack.Context != nil {
+			detail.File = stack.Context.File
+			detail.LineNumber = stack.Context.Line
+			detail.Method = stack.Context.Method
+		}
+		details = append(details, detail)
+	}
+	return &eoserr.ChainError{
+		Message: except.Message,
+		Code:    except.Code,
+		Name:    except.Name,
+		What:    except.Message,
+		Details: details,
+	}
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxLag        = 10
+	defaultProbeInterval = 10 * time.Second
+	defaultProbeTimeout  = 3 * time.Second
+	defaultCooldown      = 30 * time.Second
+)
+
+type EndpointPoolOpts struct {
+	// MaxLag is the number of blocks an endpoint can be behind the most advanced endpoint
+	// and still be preferred for requests
+	MaxLag uint32
+	// ProbeInterval is how often get_info is called on every endpoint to refresh its health,
+	// probes are triggered by requests so an idle pool does not probe, 0 disables automatic probing
+	ProbeInterval time.Duration
+	ProbeTimeout  time.Duration
+	// Cooldown is how long an endpoint that failed is avoided, after it the endpoint is tried again
+	// even if it has not been probed, defaults to 30 seconds
+	Cooldown time.Duration
+	// Transport used to make the requests, defaults to http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+func NewDefaultEndpointPoolOpts() *EndpointPoolOpts {
+	return &EndpointPoolOpts{
+		MaxLag:        defaultMaxLag,
+		ProbeInterval: defaultProbeInterval,
+		ProbeTimeout:  defaultProbeTimeout,
+		Cooldown:      defaultCooldown,
+	}
+}
+
+type EndpointStatus struct {
+	URL          string        `json:"url"`
+	Healthy      bool          `json:"healthy"`
+	Probed       bool          `json:"probed"`
+	HeadBlockNum uint32        `json:"head_block_num"`
+	Lag          uint32        `json:"lag"`
+	Latency      time.Duration `json:"latency"`
+	LastError    string        `json:"last_error,omitempty"`
+	LastProbe    time.Time     `json:"last_probe"`
+	LastFailure  time.Time     `json:"last_failure"`
+	Failures     uint64        `json:"failures"`
+}
+
+func (m *EndpointStatus) String() string {
+	return fmt.Sprintf("URL: %v, Healthy: %v, Head: %v, Lag: %v, Latency: %v, LastError: %v", m.URL, m.Healthy, m.HeadBlockNum, m.Lag, m.Latency, m.LastError)
+}
+
+type endpoint struct {
+	url    *url.URL
+	status EndpointStatus
+}
+
+// EndpointPool is an http.RoundTripper that sends each request to the healthiest of a set of
+// nodeos endpoints, endpoints are ranked by the head block lag and latency found by probing
+// get_info, requests that fail with a connection error are retried on the next endpoint
+type EndpointPool struct {
+	opts      *EndpointPoolOpts
+	transport http.RoundTripper
+	endpoints []*endpoint
+	basePath  string
+	mutex     sync.RWMutex
+	lastProbe time.Time
+	probing   int32
+}
+
+func NewEndpointPool(urls []string, opts *EndpointPoolOpts) (*EndpointPool, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("failed creating endpoint pool, at least one url is required")
+	}
+	if opts == nil {
+		opts = NewDefaultEndpointPoolOpts()
+	}
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, rawURL := range urls {
+		u, err := url.Parse(strings.TrimSuffix(rawURL, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing endpoint url: %v, error: %v", rawURL, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint url: %v, scheme and host are required", rawURL)
+		}
+		endpoints = append(endpoints, &endpoint{
+			url: u,
+			status: EndpointStatus{
+				URL:     u.String(),
+				Healthy: true,
+			},
+		})
+	}
+	return &EndpointPool{
+		opts:      opts,
+		transport: transport,
+		endpoints: endpoints,
+		basePath:  endpoints[0].url.Path,
+	}, nil
+}
+
+// URL returns the url the API using the pool should be configured with, requests made to it
+// are redirected to the endpoint chosen by the pool
+func (m *EndpointPool) URL() string {
+	return m.endpoints[0].url.String()
+}
+
+// Status returns the current health of every endpoint in the pool
+func (m *EndpointPool) Status() []*EndpointStatus {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	statuses := make([]*EndpointStatus, 0, len(m.endpoints))
+	for _, ep := range m.endpoints {
+		status := ep.status
+		statuses = append(statuses, &status)
+	}
+	return statuses
+}
+
+// Probe calls get_info on every endpoint concurrently and updates their health
+func (m *EndpointPool) Probe(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ep := range m.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			m.probe(ctx, ep)
+		}(ep)
+	}
+	wg.Wait()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastProbe = time.Now()
+	m.updateLags()
+}
+
+// Start probes the endpoints every ProbeInterval until the context is done
+func (m *EndpointPool) Start(ctx context.Context) {
+	interval := m.opts.ProbeInterval
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			m.Probe(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (m *EndpointPool) probe(ctx context.Context, ep *endpoint) {
+	timeout := m.opts.ProbeTimeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	headBlockNum, err := m.getHeadBlockNum(ctx, ep)
+	latency := time.Since(start)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ep.status.Probed = true
+	ep.status.LastProbe = time.Now()
+	if err != nil {
+		ep.status.Healthy = false
+		ep.status.LastError = err.Error()
+		ep.status.LastFailure = ep.status.LastProbe
+		return
+	}
+	ep.status.Healthy = true
+	ep.status.LastError = ""
+	ep.status.HeadBlockNum = headBlockNum
+	ep.status.Latency = latency
+}
+
+func (m *EndpointPool) getHeadBlockNum(ctx context.Context, ep *endpoint) (uint32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.resolve("/v1/chain/get_info", ""), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := m.transport.RoundTrip(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed getting chain info, error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed getting chain info, status code: %v", resp.StatusCode)
+	}
+	var info struct {
+		HeadBlockNum uint32 `json:"head_block_num"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	if err != nil {
+		return 0, fmt.Errorf("failed decoding chain info, error: %v", err)
+	}
+	return info.HeadBlockNum, nil
+}
+
+// updateLags must be called with the write lock held
+func (m *EndpointPool) updateLags() {
+	var maxHead uint32
+	for _, ep := range m.endpoints {
+		if ep.status.Healthy && ep.status.HeadBlockNum > maxHead {
+			maxHead = ep.status.HeadBlockNum
+		}
+	}
+	for _, ep := range m.endpoints {
+		if ep.status.Healthy {
+			ep.status.Lag = maxHead - ep.status.HeadBlockNum
+		}
+	}
+}
+
+func (m *EndpointPool) markFailed(ep *endpoint, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ep.status.Healthy = false
+	ep.status.LastError = err.Error()
+	ep.status.LastFailure = time.Now()
+	ep.status.Failures++
+}
+
+// restoreCooledDown marks as healthy the endpoints whose last failure is older than the cooldown,
+// so that they are tried again when the prober is not running, must be called with the write lock held
+func (m *EndpointPool) restoreCooledDown() {
+	cooldown := m.opts.Cooldown
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	for _, ep := range m.endpoints {
+		if !ep.status.Healthy && time.Since(ep.status.LastFailure) >= cooldown {
+			ep.status.Healthy = true
+		}
+	}
+}
+
+// rank returns 0 for healthy endpoints within MaxLag, 1 for healthy lagging endpoints and 2 for unhealthy ones
+func (m *EndpointPool) rank(status *EndpointStatus) int {
+	if !status.Healthy {
+		return 2
+	}
+	if status.Lag > m.opts.MaxLag {
+		return 1
+	}
+	return 0
+}
+
+// candidates returns the endpoints ordered from the healthiest to the least healthy,
+// unhealthy endpoints are kept as a last resort
+func (m *EndpointPool) candidates() []*endpoint {
+	m.maybeProbe()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.restoreCooledDown()
+	candidates := make([]*endpoint, len(m.endpoints))
+	copy(candidates, m.endpoints)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		si, sj := &candidates[i].status, &candidates[j].status
+		ri, rj := m.rank(si), m.rank(sj)
+		if ri != rj {
+			return ri < rj
+		}
+		if ri == 1 && si.Lag != sj.Lag {
+			return si.Lag < sj.Lag
+		}
+		return si.Latency < sj.Latency
+	})
+	return candidates
+}
+
+func (m *EndpointPool) maybeProbe() {
+	if m.opts.ProbeInterval <= 0 {
+		return
+	}
+	m.mutex.RLock()
+	stale := time.Since(m.lastProbe) > m.opts.ProbeInterval
+	m.mutex.RUnlock()
+	if stale && atomic.CompareAndSwapInt32(&m.probing, 0, 1) {
+		go func() {
+			defer atomic.StoreInt32(&m.probing, 0)
+			m.Probe(context.Background())
+		}()
+	}
+}
+
+// RoundTrip sends the request to the healthiest endpoint, failing over to the next one on connection errors
+func (m *EndpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	var lastErr error
+	for _, ep := range m.candidates() {
+		outReq := req.Clone(req.Context())
+		outReq.Host = ""
+		outReq.URL, lastErr = url.Parse(ep.resolve(strings.TrimPrefix(req.URL.Path, m.basePath), req.URL.RawQuery))
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		if body != nil {
+			outReq.Body = io.NopCloser(bytes.NewReader(body))
+			outReq.ContentLength = int64(len(body))
+		}
+		resp, err := m.transport.RoundTrip(outReq)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		m.markFailed(ep, err)
+	}
+	return nil, lastErr
+}
+
+func (m *endpoint) resolve(path, rawQuery string) string {
+	u := *m.url
+	u.Path = m.url.Path + path
+	u.RawQuery = rawQuery
+	return u.String()
+}
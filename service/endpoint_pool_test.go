@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+type standInNode struct {
+	*httptest.Server
+	headBlockNum uint32
+	requests     int32
+}
+
+func newStandInNode(headBlockNum uint32) *standInNode {
+	node := &standInNode{headBlockNum: headBlockNum}
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/chain/get_info" {
+			fmt.Fprintf(w, `{"head_block_num": %v}`, node.headBlockNum)
+			return
+		}
+		atomic.AddInt32(&node.requests, 1)
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"echo": %v}`, string(body))
+	}))
+	return node
+}
+
+func newTestPool(t *testing.T, nodes ...*standInNode) *service.EndpointPool {
+	urls := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		urls = append(urls, node.URL)
+	}
+	opts := service.NewDefaultEndpointPoolOpts()
+	opts.ProbeInterval = 0
+	pool, err := service.NewEndpointPool(urls, opts)
+	assert.NilError(t, err)
+	return pool
+}
+
+func poolPost(t *testing.T, pool *service.EndpointPool, path string) string {
+	client := &http.Client{Transport: pool}
+	resp, err := client.Post(pool.URL()+path, "application/json", strings.NewReader(`{"account_name":"usera"}`))
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	return string(body)
+}
+
+func TestEndpointPoolRoutesToHealthiestNode(t *testing.T) {
+	lagging := newStandInNode(100)
+	defer lagging.Close()
+	upToDate := newStandInNode(200)
+	defer upToDate.Close()
+
+	pool := newTestPool(t, lagging, upToDate)
+	pool.Probe(context.Background())
+
+	statuses := pool.Status()
+	assert.Equal(t, len(statuses), 2)
+	assert.Equal(t, statuses[0].Lag, uint32(100))
+	assert.Equal(t, statuses[1].Lag, uint32(0))
+	assert.Assert(t, statuses[0].Healthy && statuses[1].Healthy)
+
+	body := poolPost(t, pool, "/v1/chain/get_account")
+	assert.Equal(t, body, `{"echo": {"account_name":"usera"}}`)
+	assert.Equal(t, atomic.LoadInt32(&upToDate.requests), int32(1))
+	assert.Equal(t, atomic.LoadInt32(&lagging.requests), int32(0))
+}
+
+func TestEndpointPoolFailsOverOnConnectionError(t *testing.T) {
+	down := newStandInNode(300)
+	up := newStandInNode(300)
+	defer up.Close()
+
+	pool := newTestPool(t, down, up)
+	down.Close()
+
+	poolPost(t, pool, "/v1/chain/push_transaction")
+	assert.Equal(t, atomic.LoadInt32(&up.requests), int32(1))
+	statuses := pool.Status()
+	assert.Assert(t, !statuses[0].Healthy)
+	assert.Equal(t, statuses[0].Failures, uint64(1))
+
+	pool.Probe(context.Background())
+	statuses = pool.Status()
+	assert.Assert(t, !statuses[0].Healthy)
+	assert.Assert(t, statuses[0].LastError != "")
+	assert.Assert(t, statuses[1].Healthy)
+}
+
+type failingTransport struct {
+	host    string
+	failing int32
+}
+
+func (m *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.LoadInt32(&m.failing) == 1 && req.URL.Host == m.host {
+		return nil, fmt.Errorf("dial tcp %v: connection refused", m.host)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestEndpointPoolRetriesFailedEndpointsAfterCooldown(t *testing.T) {
+	flaky := newStandInNode(300)
+	defer flaky.Close()
+	stable := newStandInNode(300)
+	defer stable.Close()
+
+	transport := &failingTransport{host: flaky.Listener.Addr().String(), failing: 1}
+	opts := service.NewDefaultEndpointPoolOpts()
+	opts.ProbeInterval = 0
+	opts.Cooldown = 50 * time.Millisecond
+	opts.Transport = transport
+	pool, err := service.NewEndpointPool([]string{flaky.URL, stable.URL}, opts)
+	assert.NilError(t, err)
+
+	poolPost(t, pool, "/v1/chain/get_account")
+	assert.Assert(t, !pool.Status()[0].Healthy)
+	atomic.StoreInt32(&transport.failing, 0)
+
+	poolPost(t, pool, "/v1/chain/get_account")
+	assert.Equal(t, atomic.LoadInt32(&flaky.requests), int32(0))
+	assert.Equal(t, atomic.LoadInt32(&stable.requests), int32(2))
+
+	time.Sleep(100 * time.Millisecond)
+	poolPost(t, pool, "/v1/chain/get_account")
+	assert.Equal(t, atomic.LoadInt32(&flaky.requests), int32(1))
+	assert.Assert(t, pool.Status()[0].Healthy)
+}
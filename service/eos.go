@@ -6,13 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/sebastianmontero/eos-go"
 	eosc "github.com/sebastianmontero/eos-go"
 	"github.com/sebastianmontero/eos-go-toolbox/dto"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
 	"github.com/sebastianmontero/eos-go-toolbox/util"
 	"github.com/sebastianmontero/eos-go/ecc"
 	"github.com/sebastianmontero/eos-go/msig"
@@ -61,12 +62,37 @@ type EOS struct {
 	SetSignerFn func(*eosc.API)
 	Retries     uint
 	RetrySleep  uint
+	// RetryPolicy if set takes precedence over Retries and RetrySleep
+	RetryPolicy     RetryPolicy
+	ErrorClassifier ErrorClassifier
+	// Pool is set when the EOS object was created with multiple urls
+	Pool *EndpointPool
+	// TaposCache if set provides the reference block for transactions instead of calling get_info
+	TaposCache *TaposCache
+	// ResourceManager if set tops up the resources of accounts when transactions fail due to resource exhaustion
+	ResourceManager *ResourceManager
+	// RequiredKeysMode determines how the keys used to sign transactions are resolved
+	RequiredKeysMode RequiredKeysMode
 }
 
 type EOSOpts struct {
-	Retries    uint
-	RetrySleep uint
-	Strict     bool
+	Retries     uint
+	RetrySleep  uint
+	Strict      bool
+	RetryPolicy RetryPolicy
+	// ErrorClassifier determines which errors are retried, defaults to DefaultErrorClassifier
+	ErrorClassifier ErrorClassifier
+	// EndpointPool configures the pool used when multiple urls are provided, defaults to NewDefaultEndpointPoolOpts
+	EndpointPool *EndpointPoolOpts
+	// TaposCacheTTL enables caching the transactions reference block for the specified duration
+	TaposCacheTTL time.Duration
+	// ResourcePolicy enables the resource manager with the specified policy
+	ResourcePolicy *ResourcePolicy
+	// Signer used to sign transactions, e.g. a KeosdSigner, RemoteSigner or keystore signer,
+	// defaults to the signer already set in the API
+	Signer eosc.Signer
+	// RequiredKeysMode determines how the keys used to sign transactions are resolved, defaults to RequiredKeysChain
+	RequiredKeysMode RequiredKeysMode
 }
 
 func NewEOSFromUrl(url string) (*EOS, error) {
@@ -82,6 +108,9 @@ func NewEOSFromUrls(urls []string) (*EOS, error) {
 }
 
 func NewEOSFromUrlsWithOptions(urls []string, opts *EOSOpts) (*EOS, error) {
+	if len(urls) > 1 {
+		return NewEOSFromPool(urls, opts)
+	}
 	api, err := eosc.NewFromUrlsWithOpts(urls, &eos.APIOpts{
 		Strict: opts.Strict,
 	})
@@ -91,6 +120,25 @@ func NewEOSFromUrlsWithOptions(urls []string, opts *EOSOpts) (*EOS, error) {
 	return NewEOSWithOptions(api, opts), nil
 }
 
+// NewEOSFromPool creates an EOS object whose requests are routed through an EndpointPool,
+// reads go to the healthiest endpoint and requests fail over to other endpoints on connection errors
+func NewEOSFromPool(urls []string, opts *EOSOpts) (*EOS, error) {
+	pool, err := NewEndpointPool(urls, opts.EndpointPool)
+	if err != nil {
+		return nil, err
+	}
+	api, err := eosc.NewFromUrlsWithOpts([]string{pool.URL()}, &eos.APIOpts{
+		Strict: opts.Strict,
+	})
+	if err != nil {
+		return nil, err
+	}
+	api.HttpClient = &http.Client{Transport: pool}
+	client := NewEOSWithOptions(api, opts)
+	client.Pool = pool
+	return client, nil
+}
+
 func NewEOS(api *eosc.API) *EOS {
 	return NewEOSWithOptions(api, &EOSOpts{
 		Retries:    retries,
@@ -100,11 +148,24 @@ func NewEOS(api *eosc.API) *EOS {
 }
 
 func NewEOSWithOptions(api *eosc.API, opts *EOSOpts) *EOS {
-	return &EOS{
-		API:        api,
-		Retries:    opts.Retries,
-		RetrySleep: opts.RetrySleep,
+	client := &EOS{
+		API:              api,
+		Retries:          opts.Retries,
+		RetrySleep:       opts.RetrySleep,
+		RetryPolicy:      opts.RetryPolicy,
+		ErrorClassifier:  opts.ErrorClassifier,
+		RequiredKeysMode: opts.RequiredKeysMode,
 	}
+	if opts.TaposCacheTTL > 0 {
+		client.EnableTaposCache(opts.TaposCacheTTL)
+	}
+	if opts.Signer != nil {
+		api.SetSigner(opts.Signer)
+	}
+	if opts.ResourcePolicy != nil {
+		client.ResourceManager = NewResourceManager(client, opts.ResourcePolicy)
+	}
+	return client
 }
 
 func NameFromString(s string) (eos.Name, error) {
@@ -140,7 +201,8 @@ func GetEOSIOPublicKey() *ecc.PublicKey {
 func GetKey(privateKey string) (*ecc.PrivateKey, error) {
 	key, err := ecc.NewPrivateKey(privateKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed creating key for string key: %v, error: %v", privateKey, err)
+		// the key is never included in the error, only the redacted parser error
+		return nil, fmt.Errorf("failed creating key from private key, error: %v", util.RedactError(err))
 	}
 	return key, nil
 }
@@ -150,6 +212,10 @@ func (m *EOS) ChainID() string {
 }
 
 func (m *EOS) AddKey(privateKey string) (*ecc.PublicKey, error) {
+	return m.AddKeyCtx(context.Background(), privateKey)
+}
+
+func (m *EOS) AddKeyCtx(ctx context.Context, privateKey string) (*ecc.PublicKey, error) {
 	// logger.Infof("PKey: %v", pkey)
 	if m.API.Signer == nil {
 		m.API.SetSigner(&eosc.KeyBag{})
@@ -158,9 +224,9 @@ func (m *EOS) AddKey(privateKey string) (*ecc.PublicKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = m.API.Signer.ImportPrivateKey(context.Background(), privateKey)
+	err = m.API.Signer.ImportPrivateKey(ctx, privateKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed importing key, error: %v", err)
+		return nil, fmt.Errorf("failed importing key, error: %v", util.RedactError(err))
 	}
 	publicKey := key.PublicKey()
 	return &publicKey, nil
@@ -171,53 +237,59 @@ func (m *EOS) AddEOSIOKey() (*ecc.PublicKey, error) {
 }
 
 func (m *EOS) Trx(actions ...*eosc.Action) (*eosc.PushTransactionFullResp, error) {
-	return m.TrxWithRetries(m.Retries, actions...)
+	return m.TrxCtx(context.Background(), actions...)
 }
 
-func (m *EOS) TrxWithRetries(retries uint, actions ...*eosc.Action) (*eosc.PushTransactionFullResp, error) {
+func (m *EOS) TrxCtx(ctx context.Context, actions ...*eosc.Action) (*eosc.PushTransactionFullResp, error) {
 	// for _, action := range actions {
 	// 	logger.Infof("Trx Account: %v Name: %v, Authorization: %v, Data: %v", action.Account, action.Name, action.Authorization, action.ActionData)
 
 	// }
-	if m.API.Signer == nil && m.SetSignerFn != nil {
-		m.SetSignerFn(m.API)
-	}
-	resp, err := m.API.SignPushActions(context.Background(), actions...)
+	m.ensureSigner()
+	var resp *eosc.PushTransactionFullResp
+	err := m.withRetries(ctx, func() (err error) {
+		resp, err = m.pushWithTopUps(ctx, actions)
+		return
+	})
 	if err != nil {
-		if retries > 0 {
-			if isRetryableError(err) {
-				time.Sleep(time.Duration(retrySleep) * time.Second)
-				return m.TrxWithRetries(retries-1, actions...)
-			}
-		}
 		return nil, err
 	}
 	return resp, nil
 }
 
-func isRetryableError(err error) bool {
-	errMsg := err.Error()
-	// fmt.Println("Error: ", errMsg)
-	return strings.Contains(errMsg, "deadline") ||
-		strings.Contains(errMsg, "connection reset by peer") ||
-		strings.Contains(errMsg, "Transaction took too long") ||
-		strings.Contains(errMsg, "exceeded the current CPU usage limit") ||
-		strings.Contains(errMsg, "ABI serialization time has exceeded") ||
-		strings.Contains(errMsg, "Invalid Reference Block: Transaction's reference block did not match. Is this transaction from a different fork")
+func (m *EOS) ensureSigner() {
+	if m.API.Signer == nil && m.SetSignerFn != nil {
+		m.SetSignerFn(m.API)
+	}
+}
+
+func (m *EOS) TrxWithRetries(retries uint, actions ...*eosc.Action) (*eosc.PushTransactionFullResp, error) {
+	return m.TrxWithRetriesCtx(context.Background(), retries, actions...)
+}
 
+func (m *EOS) TrxWithRetriesCtx(ctx context.Context, retries uint, actions ...*eosc.Action) (*eosc.PushTransactionFullResp, error) {
+	return m.TrxCtx(m.WithMaxRetries(ctx, retries), actions...)
 }
 
 func (m *EOS) SimpleTrx(contract, actionName, permissionLevel, data interface{}) (*eosc.PushTransactionFullResp, error) {
+	return m.SimpleTrxCtx(context.Background(), contract, actionName, permissionLevel, data)
+}
+
+func (m *EOS) SimpleTrxCtx(ctx context.Context, contract, actionName, permissionLevel, data interface{}) (*eosc.PushTransactionFullResp, error) {
 	action, err := m.BuildAction(contract, actionName, permissionLevel, data)
 	if err != nil {
 		return nil, err
 	}
-	return m.Trx(action)
+	return m.TrxCtx(ctx, action)
 }
 
 func (m *EOS) DebugTrx(contract, actionName, permissionLevel, data interface{}) (*eosc.PushTransactionFullResp, error) {
-	txOpts := &eosc.TxOptions{}
-	if err := txOpts.FillFromChain(context.Background(), m.API); err != nil {
+	return m.DebugTrxCtx(context.Background(), contract, actionName, permissionLevel, data)
+}
+
+func (m *EOS) DebugTrxCtx(ctx context.Context, contract, actionName, permissionLevel, data interface{}) (*eosc.PushTransactionFullResp, error) {
+	txOpts, err := m.txOptions(ctx)
+	if err != nil {
 		return nil, err
 	}
 	action, err := m.BuildAction(contract, actionName, permissionLevel, data)
@@ -226,7 +298,7 @@ func (m *EOS) DebugTrx(contract, actionName, permissionLevel, data interface{})
 	}
 	fmt.Println("Action Data: ", action.ActionData.Data)
 	tx := eosc.NewTransaction([]*eosc.Action{action}, txOpts)
-	signedTx, packedTx, err := m.API.SignTransaction(context.Background(), tx, txOpts.ChainID, eosc.CompressionNone)
+	signedTx, packedTx, err := m.SignTrxCtx(ctx, tx, txOpts.ChainID)
 	if err != nil {
 		return nil, err
 	}
@@ -237,12 +309,21 @@ func (m *EOS) DebugTrx(contract, actionName, permissionLevel, data interface{})
 	}
 
 	fmt.Println(string(content))
-	return m.API.PushTransaction(context.Background(), packedTx)
+	var resp *eosc.PushTransactionFullResp
+	err = m.withRetries(ctx, func() (err error) {
+		resp, err = m.API.PushTransaction(ctx, packedTx)
+		return
+	})
+	return resp, err
 }
 
 func (m *EOS) BuildTrx(expireIn time.Duration, actions ...*eosc.Action) (*eosc.Transaction, error) {
-	txOpts := &eosc.TxOptions{}
-	if err := txOpts.FillFromChain(context.Background(), m.API); err != nil {
+	return m.BuildTrxCtx(context.Background(), expireIn, actions...)
+}
+
+func (m *EOS) BuildTrxCtx(ctx context.Context, expireIn time.Duration, actions ...*eosc.Action) (*eosc.Transaction, error) {
+	txOpts, err := m.txOptions(ctx)
+	if err != nil {
 		return nil, fmt.Errorf("failed getting txOptions to build trx, error: %v", err)
 	}
 	tx := eosc.NewTransaction(actions, txOpts)
@@ -253,87 +334,82 @@ func (m *EOS) BuildTrx(expireIn time.Duration, actions ...*eosc.Action) (*eosc.T
 }
 
 func (m *EOS) CreateAccount(accountName interface{}, publicKey *ecc.PublicKey, failIfExists bool) (eosc.AccountName, error) {
-	account, err := util.ToAccountName(accountName)
-	if err != nil {
-		return "", err
-	}
-	if !failIfExists {
-		accountData, err := m.GetAccount(accountName)
-		if err != nil {
-			return "", err
-		}
-		if accountData != nil {
-			return account, nil
-		}
-	}
-	_, err = m.Trx(system.NewNewAccount("eosio", account, *publicKey))
-	if err != nil {
-		if failIfExists || !strings.Contains(err.Error(), "name is already taken") {
-			return "", err
-		}
-	}
-	return account, nil
+	return m.CreateAccountCtx(context.Background(), accountName, publicKey, failIfExists)
+}
+
+// CreateAccountCtx creates the account with eosio as the creator, without buying resources for it,
+// use CreateAccountWithOpts on chains with the system contract deployed
+func (m *EOS) CreateAccountCtx(ctx context.Context, accountName interface{}, publicKey *ecc.PublicKey, failIfExists bool) (eosc.AccountName, error) {
+	opts := NewCreateAccountOpts(publicKey)
+	opts.FailIfExists = failIfExists
+	return m.CreateAccountWithOptsCtx(ctx, accountName, opts)
 }
 
 func (m *EOS) CreateRandomAccount(publicKey *ecc.PublicKey) (eosc.AccountName, error) {
+	return m.CreateRandomAccountCtx(context.Background(), publicKey)
+}
+
+func (m *EOS) CreateRandomAccountCtx(ctx context.Context, publicKey *ecc.PublicKey) (eosc.AccountName, error) {
 
 	if publicKey == nil {
 		publicKey = GetEOSIOPublicKey()
 	}
-	return m.CreateAccount(util.RandAccountName(), publicKey, true)
+	return m.CreateAccountCtx(ctx, util.RandAccountName(), publicKey, true)
 }
 
+// GetAccount returns the account data, or an ErrAccountNotFound error if the account does not exist
 func (m *EOS) GetAccount(accountName interface{}) (*eosc.AccountResp, error) {
-	account, err := util.ToAccountName(accountName)
+	return m.GetAccountCtx(context.Background(), accountName)
+}
+
+func (m *EOS) GetAccountCtx(ctx context.Context, accountName interface{}) (*eosc.AccountResp, error) {
+	accountData, err := m.FindAccountCtx(ctx, accountName)
 	if err != nil {
 		return nil, err
 	}
-	accountData, err := m.API.GetAccount(context.Background(), account)
-	if err != nil {
-		if strings.Contains(err.Error(), "resource not found") || strings.Contains(err.Error(), "unknown key") {
-			return nil, nil
-		}
-		return nil, err
+	if accountData == nil {
+		return nil, fmt.Errorf("%w: %v", eoserr.ErrAccountNotFound, accountName)
 	}
 	return accountData, nil
 }
 
-// SetContract sets contract, if publicKey is not nil it creates the account
-func (m *EOS) SetContract(accountName interface{}, wasmFile, abiFile string, publicKey *ecc.PublicKey) (*eosc.PushTransactionFullResp, error) {
+// FindAccount returns the account data, or nil if the account does not exist
+func (m *EOS) FindAccount(accountName interface{}) (*eosc.AccountResp, error) {
+	return m.FindAccountCtx(context.Background(), accountName)
+}
 
-	if publicKey != nil {
-		_, err := m.CreateAccount(accountName, publicKey, false)
-		if err != nil {
-			return nil, err
-		}
-	}
+func (m *EOS) FindAccountCtx(ctx context.Context, accountName interface{}) (*eosc.AccountResp, error) {
 	account, err := util.ToAccountName(accountName)
 	if err != nil {
 		return nil, err
 	}
-	setCodeAction, err := system.NewSetCode(account, wasmFile)
-	if err != nil {
-		return nil, fmt.Errorf("unable construct set_code action: %v", err)
-	}
-
-	_, err = m.Trx(setCodeAction)
+	var accountData *eosc.AccountResp
+	err = m.withRetries(ctx, func() (err error) {
+		accountData, err = m.API.GetAccount(ctx, account)
+		return
+	})
 	if err != nil {
-		if !strings.Contains(err.Error(), "contract is already running this version of code") {
-			return nil, err
+		if eoserr.IsAccountNotFound(err) {
+			return nil, nil
 		}
+		return nil, err
 	}
+	return accountData, nil
+}
 
-	setAbiAction, err := system.NewSetABI(account, abiFile)
-	if err != nil {
-		return nil, fmt.Errorf("unable construct set_abi action: %v", err)
-	}
-	resp, err := m.Trx(setAbiAction)
-	if err != nil {
-		if !strings.Contains(err.Error(), "contract is already running this version of code") {
-			return nil, err
-		}
-	}
-	return resp, nil
+// SetContract sets contract, if publicKey is not nil it creates the account, only the code and ABI that
+// differ from the ones on chain are pushed, in one transaction, returns a report of what was updated
+func (m *EOS) SetContract(accountName interface{}, wasmFile, abiFile string, publicKey *ecc.PublicKey) (*DeploymentReport, error) {
+	return m.SetContractCtx(context.Background(), accountName, wasmFile, abiFile, publicKey)
+}
+
+// SetContractCtx sets contract, if publicKey is not nil it creates the account, the ABI is not checked
+// for breaking changes, use SetContractWithOpts for that
+func (m *EOS) SetContractCtx(ctx context.Context, accountName interface{}, wasmFile, abiFile string, publicKey *ecc.PublicKey) (*DeploymentReport, error) {
+	return m.SetContractWithOptsCtx(ctx, accountName, wasmFile, abiFile, &SetContractOpts{
+		PublicKey:        publicKey,
+		AllowBreakingABI: true,
+	})
 }
 
 func (m *EOS) GetSetContractActions(accountName interface{}, wasmFile, abiFile string) ([]*eosc.Action, error) {
@@ -357,10 +433,33 @@ func (m *EOS) GetSetContractActions(accountName interface{}, wasmFile, abiFile s
 	}, nil
 }
 
+// GetAccountPermission returns the account permission, or an ErrAccountNotFound or ErrPermissionNotFound
+// error if the account or the permission do not exist
 func (m *EOS) GetAccountPermission(accountName interface{}, permissionName string) (*eosc.Permission, error) {
-	account, err := m.GetAccount(accountName)
+	return m.GetAccountPermissionCtx(context.Background(), accountName, permissionName)
+}
+
+func (m *EOS) GetAccountPermissionCtx(ctx context.Context, accountName interface{}, permissionName string) (*eosc.Permission, error) {
+	permission, err := m.FindAccountPermissionCtx(ctx, accountName, permissionName)
+	if err != nil {
+		return nil, err
+	}
+	if permission == nil {
+		return nil, fmt.Errorf("%w: %v@%v", eoserr.ErrPermissionNotFound, accountName, permissionName)
+	}
+	return permission, nil
+}
+
+// FindAccountPermission returns the account permission, or nil if the permission does not exist,
+// it fails with an ErrAccountNotFound error if the account does not exist
+func (m *EOS) FindAccountPermission(accountName interface{}, permissionName string) (*eosc.Permission, error) {
+	return m.FindAccountPermissionCtx(context.Background(), accountName, permissionName)
+}
+
+func (m *EOS) FindAccountPermissionCtx(ctx context.Context, accountName interface{}, permissionName string) (*eosc.Permission, error) {
+	account, err := m.GetAccountCtx(ctx, accountName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get account object for name: %v, error: %v", accountName, err)
+		return nil, fmt.Errorf("failed to get account object for name: %v, error: %w", accountName, err)
 	}
 	for _, permission := range account.Permissions {
 		if permission.PermName == permissionName {
@@ -371,13 +470,17 @@ func (m *EOS) GetAccountPermission(accountName interface{}, permissionName strin
 }
 
 func (m *EOS) SetEOSIOCode(accountName interface{}) (bool, error) {
+	return m.SetEOSIOCodeCtx(context.Background(), accountName)
+}
+
+func (m *EOS) SetEOSIOCodeCtx(ctx context.Context, accountName interface{}) (bool, error) {
 
-	codePermissionAction, err := m.GetSetEOSIOCodeAction(accountName)
+	codePermissionAction, err := m.GetSetEOSIOCodeActionCtx(ctx, accountName)
 	if err != nil {
 		return false, fmt.Errorf("failed setting eosio.code permission for account: %v, error: %v", accountName, err)
 	}
 	if codePermissionAction != nil {
-		_, err = m.Trx(codePermissionAction)
+		_, err = m.TrxCtx(ctx, codePermissionAction)
 		if err != nil {
 			return false, fmt.Errorf("error setting eosio.code permission for account: %v, error: %v", accountName, err)
 		}
@@ -388,13 +491,17 @@ func (m *EOS) SetEOSIOCode(accountName interface{}) (bool, error) {
 }
 
 func (m *EOS) ProposeSetEOSIOCode(proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}) (*ProposeResponse, error) {
+	return m.ProposeSetEOSIOCodeCtx(context.Background(), proposerName, requested, expireIn, accountName)
+}
+
+func (m *EOS) ProposeSetEOSIOCodeCtx(ctx context.Context, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}) (*ProposeResponse, error) {
 
-	codePermissionAction, err := m.GetSetEOSIOCodeAction(accountName)
+	codePermissionAction, err := m.GetSetEOSIOCodeActionCtx(ctx, accountName)
 	if err != nil {
 		return nil, fmt.Errorf("failed proposing set eosio.code permission for account: %v, error: %v", accountName, err)
 	}
 	if codePermissionAction != nil {
-		response, err := m.ProposeMultiSig(proposerName, requested, expireIn, codePermissionAction)
+		response, err := m.ProposeMultiSigCtx(ctx, proposerName, requested, expireIn, codePermissionAction)
 		if err != nil {
 			return nil, fmt.Errorf("error proposing set eosio.code permission for account: %v, error: %v", accountName, err)
 		}
@@ -405,15 +512,19 @@ func (m *EOS) ProposeSetEOSIOCode(proposerName interface{}, requested []eosc.Per
 }
 
 func (m *EOS) GetSetEOSIOCodeAction(accountName interface{}) (*eosc.Action, error) {
+	return m.GetSetEOSIOCodeActionCtx(context.Background(), accountName)
+}
+
+func (m *EOS) GetSetEOSIOCodeActionCtx(ctx context.Context, accountName interface{}) (*eosc.Action, error) {
 
 	acct, err := util.ToAccountName(accountName)
 	if err != nil {
 		return nil, err
 	}
 
-	permission, err := m.GetAccountPermission(accountName, "active")
+	permission, err := m.GetAccountPermissionCtx(ctx, accountName, "active")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active permission for account: %v, error: %v", accountName, err)
+		return nil, fmt.Errorf("failed to get active permission for account: %v, error: %w", accountName, err)
 	}
 	accountAuthorityPos, err := FindAccountAuthority(permission, acct, "eosio.code")
 	if err != nil {
@@ -465,17 +576,23 @@ func (m *EOS) ActivatePreactivateFeature(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	return m.API.ScheduleProducerProtocolFeatureActivations(ctx, []eosc.Checksum256{v})
+	return m.withRetries(ctx, func() error {
+		return m.API.ScheduleProducerProtocolFeatureActivations(ctx, []eosc.Checksum256{v})
+	})
 }
 
 func (m *EOS) ActivateAllProtocolFeatures(ctx context.Context) error {
-	features, err := m.API.GetProducerProtocolFeatures(context.Background())
+	var features []eosc.ProtocolFeature
+	err := m.withRetries(ctx, func() (err error) {
+		features, err = m.API.GetProducerProtocolFeatures(ctx)
+		return
+	})
 	if err != nil {
 		return err
 	}
 	for _, feature := range features {
 		if feature.FeatureDigest.String() != preactivateFeatureDigest {
-			_, err = m.Trx(system.NewActivateFeature(eos.Checksum256(feature.FeatureDigest)))
+			_, err = m.TrxCtx(ctx, system.NewActivateFeature(eos.Checksum256(feature.FeatureDigest)))
 			if err != nil {
 				return err
 			}
@@ -485,6 +602,10 @@ func (m *EOS) ActivateAllProtocolFeatures(ctx context.Context) error {
 }
 
 func (m *EOS) CreateSimplePermission(accountName, newPermissionName interface{}, publicKey *ecc.PublicKey) error {
+	return m.CreateSimplePermissionCtx(context.Background(), accountName, newPermissionName, publicKey)
+}
+
+func (m *EOS) CreateSimplePermissionCtx(ctx context.Context, accountName, newPermissionName interface{}, publicKey *ecc.PublicKey) error {
 	acct, err := util.ToAccountName(accountName)
 	if err != nil {
 		return err
@@ -514,7 +635,7 @@ func (m *EOS) CreateSimplePermission(accountName, newPermissionName interface{},
 			Waits: []eosc.WaitWeight{},
 		}, permission)
 
-	_, err = m.Trx(codePermissionAction)
+	_, err = m.TrxCtx(ctx, codePermissionAction)
 	if err != nil {
 		return fmt.Errorf("error creating permission: %v, for account: %v, error: %v", newPermission, acct, err)
 	}
@@ -522,6 +643,10 @@ func (m *EOS) CreateSimplePermission(accountName, newPermissionName interface{},
 }
 
 func (m *EOS) LinkPermission(accountName, actionName, permissionName interface{}, failIfExists bool) error {
+	return m.LinkPermissionCtx(context.Background(), accountName, actionName, permissionName, failIfExists)
+}
+
+func (m *EOS) LinkPermissionCtx(ctx context.Context, accountName, actionName, permissionName interface{}, failIfExists bool) error {
 	acct, err := util.ToAccountName(accountName)
 	if err != nil {
 		return err
@@ -536,9 +661,9 @@ func (m *EOS) LinkPermission(accountName, actionName, permissionName interface{}
 		return err
 	}
 	linkAction := system.NewLinkAuth(acct, acct, action, eosc.PermissionName(permission))
-	_, err = m.Trx(linkAction)
+	_, err = m.TrxCtx(ctx, linkAction)
 	if err != nil {
-		if failIfExists || !strings.Contains(err.Error(), "new requirement is same as old") {
+		if failIfExists || !eoserr.IsAssertFailure(err, "new requirement is same as old") {
 			return fmt.Errorf("error linking permission: %v, to action %v:%v, error: %v", permission, acct, action, err)
 		}
 	}
@@ -546,8 +671,12 @@ func (m *EOS) LinkPermission(accountName, actionName, permissionName interface{}
 }
 
 func (m *EOS) AreTablesEmpty(code string, tables []string) (bool, error) {
+	return m.AreTablesEmptyCtx(context.Background(), code, tables)
+}
+
+func (m *EOS) AreTablesEmptyCtx(ctx context.Context, code string, tables []string) (bool, error) {
 	for _, table := range tables {
-		empty, err := m.IsTableEmpty(code, table)
+		empty, err := m.IsTableEmptyCtx(ctx, code, table)
 		if err != nil {
 			return false, err
 		}
@@ -559,12 +688,16 @@ func (m *EOS) AreTablesEmpty(code string, tables []string) (bool, error) {
 }
 
 func (m *EOS) IsTableEmpty(code, table string) (bool, error) {
-	scopes, err := m.GetAllTableScopes(code, table)
+	return m.IsTableEmptyCtx(context.Background(), code, table)
+}
+
+func (m *EOS) IsTableEmptyCtx(ctx context.Context, code, table string) (bool, error) {
+	scopes, err := m.GetAllTableScopesCtx(ctx, code, table)
 	if err != nil {
 		return false, fmt.Errorf("error getting table: %v scopes, error: %v", table, err)
 	}
 	for _, scope := range scopes {
-		empty, err := m.IsTableScopeEmpty(code, scope.Scope, table)
+		empty, err := m.IsTableScopeEmptyCtx(ctx, code, scope.Scope, table)
 		if err != nil {
 			return false, err
 		}
@@ -576,6 +709,10 @@ func (m *EOS) IsTableEmpty(code, table string) (bool, error) {
 }
 
 func (m *EOS) IsTableScopeEmpty(code, scope, table string) (bool, error) {
+	return m.IsTableScopeEmptyCtx(context.Background(), code, scope, table)
+}
+
+func (m *EOS) IsTableScopeEmptyCtx(ctx context.Context, code, scope, table string) (bool, error) {
 	req := &eosc.GetTableRowsRequest{
 		Code:  code,
 		Scope: scope,
@@ -583,7 +720,7 @@ func (m *EOS) IsTableScopeEmpty(code, scope, table string) (bool, error) {
 		Limit: 1,
 	}
 	var rows []interface{}
-	err := m.GetTableRows(*req, &rows)
+	err := m.GetTableRowsCtx(ctx, *req, &rows)
 	if err != nil {
 		return false, fmt.Errorf("error getting table: %v, scope: %v rows, error: %v", table, scope, err)
 	}
@@ -591,7 +728,11 @@ func (m *EOS) IsTableScopeEmpty(code, scope, table string) (bool, error) {
 }
 
 func (m *EOS) GetAllTableRows(req eosc.GetTableRowsRequest, keyName string, structuredRows interface{}) error {
-	allRows, err := m.GetAllTableRowsAsMap(req, keyName)
+	return m.GetAllTableRowsCtx(context.Background(), req, keyName, structuredRows)
+}
+
+func (m *EOS) GetAllTableRowsCtx(ctx context.Context, req eosc.GetTableRowsRequest, keyName string, structuredRows interface{}) error {
+	allRows, err := m.GetAllTableRowsAsMapCtx(ctx, req, keyName)
 	if err != nil {
 		return err
 	}
@@ -614,14 +755,26 @@ func defaultGetIndexValue(keyValue string) (string, error) {
 }
 
 func (m *EOS) GetAllTableRowsAsMap(req eosc.GetTableRowsRequest, keyName string) ([]map[string]interface{}, error) {
-	return m.GetAllTableRowsFromAsMap(req, keyName, "", nil)
+	return m.GetAllTableRowsAsMapCtx(context.Background(), req, keyName)
+}
+
+func (m *EOS) GetAllTableRowsAsMapCtx(ctx context.Context, req eosc.GetTableRowsRequest, keyName string) ([]map[string]interface{}, error) {
+	return m.GetAllTableRowsFromAsMapCtx(ctx, req, keyName, "", nil)
 }
 
 func (m *EOS) GetAllTableRowsFromAsMap(req eosc.GetTableRowsRequest, keyName, startFrom string, getIndexValue GetIndexValue) ([]map[string]interface{}, error) {
-	return m.GetAllTableRowsFromTillAsMap(req, keyName, startFrom, getIndexValue, "")
+	return m.GetAllTableRowsFromAsMapCtx(context.Background(), req, keyName, startFrom, getIndexValue)
+}
+
+func (m *EOS) GetAllTableRowsFromAsMapCtx(ctx context.Context, req eosc.GetTableRowsRequest, keyName, startFrom string, getIndexValue GetIndexValue) ([]map[string]interface{}, error) {
+	return m.GetAllTableRowsFromTillAsMapCtx(ctx, req, keyName, startFrom, getIndexValue, "")
 }
 
 func (m *EOS) GetAllTableRowsFromTillAsMap(req eosc.GetTableRowsRequest, keyName, startFrom string, getIndexValue GetIndexValue, upperBound string) ([]map[string]interface{}, error) {
+	return m.GetAllTableRowsFromTillAsMapCtx(context.Background(), req, keyName, startFrom, getIndexValue, upperBound)
+}
+
+func (m *EOS) GetAllTableRowsFromTillAsMapCtx(ctx context.Context, req eosc.GetTableRowsRequest, keyName, startFrom string, getIndexValue GetIndexValue, upperBound string) ([]map[string]interface{}, error) {
 	allRows := make([]map[string]interface{}, 0)
 	lowerBound := startFrom
 	if getIndexValue == nil {
@@ -639,7 +792,7 @@ func (m *EOS) GetAllTableRowsFromTillAsMap(req eosc.GetTableRowsRequest, keyName
 		req.UpperBound = upperBound
 		req.Limit = 2
 		var rows []map[string]interface{}
-		err = m.GetTableRows(req, &rows)
+		err = m.GetTableRowsCtx(ctx, req, &rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed getting table rows %v", err)
 		}
@@ -659,20 +812,18 @@ func (m *EOS) GetAllTableRowsFromTillAsMap(req eosc.GetTableRowsRequest, keyName
 }
 
 func (m *EOS) GetTableRows(request eosc.GetTableRowsRequest, rows interface{}) error {
-	return m.GetTableRowsRetries(request, rows, m.Retries)
+	return m.GetTableRowsCtx(context.Background(), request, rows)
 }
 
-func (m *EOS) GetTableRowsRetries(request eosc.GetTableRowsRequest, rows interface{}, retries uint) error {
+func (m *EOS) GetTableRowsCtx(ctx context.Context, request eosc.GetTableRowsRequest, rows interface{}) error {
 
 	request.JSON = true
-	response, err := m.API.GetTableRows(context.Background(), request)
+	var response *eosc.GetTableRowsResp
+	err := m.withRetries(ctx, func() (err error) {
+		response, err = m.API.GetTableRows(ctx, request)
+		return
+	})
 	if err != nil {
-		if retries > 0 {
-			if isRetryableError(err) {
-				time.Sleep(time.Duration(retrySleep) * time.Second)
-				return m.GetTableRowsRetries(request, rows, retries-1)
-			}
-		}
 		return fmt.Errorf("get table rows %v", err)
 	}
 
@@ -683,7 +834,19 @@ func (m *EOS) GetTableRowsRetries(request eosc.GetTableRowsRequest, rows interfa
 	return nil
 }
 
+func (m *EOS) GetTableRowsRetries(request eosc.GetTableRowsRequest, rows interface{}, retries uint) error {
+	return m.GetTableRowsRetriesCtx(context.Background(), request, rows, retries)
+}
+
+func (m *EOS) GetTableRowsRetriesCtx(ctx context.Context, request eosc.GetTableRowsRequest, rows interface{}, retries uint) error {
+	return m.GetTableRowsCtx(m.WithMaxRetries(ctx, retries), request, rows)
+}
+
 func (m *EOS) GetAllTableScopes(code, table string) ([]*TableScope, error) {
+	return m.GetAllTableScopesCtx(context.Background(), code, table)
+}
+
+func (m *EOS) GetAllTableScopesCtx(ctx context.Context, code, table string) ([]*TableScope, error) {
 	scopes := make([]*TableScope, 0)
 	req := eosc.GetTableByScopeRequest{
 		Code:  code,
@@ -691,7 +854,7 @@ func (m *EOS) GetAllTableScopes(code, table string) ([]*TableScope, error) {
 		Limit: 10000,
 	}
 	for {
-		resp, err := m.GetTableScopes(req)
+		resp, err := m.GetTableScopesCtx(ctx, req)
 		if err != nil {
 			return nil, err
 		}
@@ -704,19 +867,17 @@ func (m *EOS) GetAllTableScopes(code, table string) ([]*TableScope, error) {
 }
 
 func (m *EOS) GetTableScopes(request eosc.GetTableByScopeRequest) (*TableScopesResp, error) {
-	return m.GetTableScopesRetries(request, m.Retries)
+	return m.GetTableScopesCtx(context.Background(), request)
 }
 
-func (m *EOS) GetTableScopesRetries(request eosc.GetTableByScopeRequest, retries uint) (*TableScopesResp, error) {
+func (m *EOS) GetTableScopesCtx(ctx context.Context, request eosc.GetTableByScopeRequest) (*TableScopesResp, error) {
 
-	response, err := m.API.GetTableByScope(context.Background(), request)
+	var response *eosc.GetTableByScopeResp
+	err := m.withRetries(ctx, func() (err error) {
+		response, err = m.API.GetTableByScope(ctx, request)
+		return
+	})
 	if err != nil {
-		if retries > 0 {
-			if isRetryableError(err) {
-				time.Sleep(time.Duration(m.RetrySleep) * time.Second)
-				return m.GetTableScopesRetries(request, retries-1)
-			}
-		}
 		return nil, fmt.Errorf("get table scopes %v", err)
 	}
 	var scopes []*TableScope
@@ -731,6 +892,14 @@ func (m *EOS) GetTableScopesRetries(request eosc.GetTableByScopeRequest, retries
 	}, nil
 }
 
+func (m *EOS) GetTableScopesRetries(request eosc.GetTableByScopeRequest, retries uint) (*TableScopesResp, error) {
+	return m.GetTableScopesRetriesCtx(context.Background(), request, retries)
+}
+
+func (m *EOS) GetTableScopesRetriesCtx(ctx context.Context, request eosc.GetTableByScopeRequest, retries uint) (*TableScopesResp, error) {
+	return m.GetTableScopesCtx(m.WithMaxRetries(ctx, retries), request)
+}
+
 func (m *EOS) GetComposedIndexValue(firstValue interface{}, secondValue interface{}) (string, error) {
 
 	firstInt64, err := m.getUInt64Value(firstValue)
@@ -789,7 +958,28 @@ func (m *EOS) getUInt64Value(value interface{}) (uint64, error) {
 	}
 }
 
+// GetBalance returns the account balance, or an ErrBalanceNotFound error if the account has no balance for the symbol
 func (m *EOS) GetBalance(accountName, symbol, contractName interface{}) (*eosc.Asset, error) {
+	return m.GetBalanceCtx(context.Background(), accountName, symbol, contractName)
+}
+
+func (m *EOS) GetBalanceCtx(ctx context.Context, accountName, symbol, contractName interface{}) (*eosc.Asset, error) {
+	balance, err := m.FindBalanceCtx(ctx, accountName, symbol, contractName)
+	if err != nil {
+		return nil, err
+	}
+	if balance == nil {
+		return nil, fmt.Errorf("%w: account: %v, symbol: %v, contract: %v", eoserr.ErrBalanceNotFound, accountName, symbol, contractName)
+	}
+	return balance, nil
+}
+
+// FindBalance returns the account balance, or nil if the account has no balance for the symbol
+func (m *EOS) FindBalance(accountName, symbol, contractName interface{}) (*eosc.Asset, error) {
+	return m.FindBalanceCtx(context.Background(), accountName, symbol, contractName)
+}
+
+func (m *EOS) FindBalanceCtx(ctx context.Context, accountName, symbol, contractName interface{}) (*eosc.Asset, error) {
 	account, err := util.ToAccountName(accountName)
 	if err != nil {
 		return nil, err
@@ -802,7 +992,11 @@ func (m *EOS) GetBalance(accountName, symbol, contractName interface{}) (*eosc.A
 	if err != nil {
 		return nil, err
 	}
-	assets, err := m.API.GetCurrencyBalance(context.Background(), account, sym.MustSymbolCode().String(), contract)
+	var assets []eosc.Asset
+	err = m.withRetries(ctx, func() (err error) {
+		assets, err = m.API.GetCurrencyBalance(ctx, account, sym.MustSymbolCode().String(), contract)
+		return
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get currency balance, error: %v", err)
 	}
@@ -813,6 +1007,10 @@ func (m *EOS) GetBalance(accountName, symbol, contractName interface{}) (*eosc.A
 }
 
 func (m *EOS) GetCurrencyStat(symbol, contractName interface{}) (*eosc.GetCurrencyStatsResp, error) {
+	return m.GetCurrencyStatCtx(context.Background(), symbol, contractName)
+}
+
+func (m *EOS) GetCurrencyStatCtx(ctx context.Context, symbol, contractName interface{}) (*eosc.GetCurrencyStatsResp, error) {
 
 	contract, err := util.ToAccountName(contractName)
 	if err != nil {
@@ -822,7 +1020,11 @@ func (m *EOS) GetCurrencyStat(symbol, contractName interface{}) (*eosc.GetCurren
 	if err != nil {
 		return nil, err
 	}
-	stats, err := m.API.GetCurrencyStats(context.Background(), contract, sym.MustSymbolCode().String())
+	var stats *eosc.GetCurrencyStatsResp
+	err = m.withRetries(ctx, func() (err error) {
+		stats, err = m.API.GetCurrencyStats(ctx, contract, sym.MustSymbolCode().String())
+		return
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get currency stat, error: %v", err)
 	}
@@ -864,17 +1066,21 @@ func (m *EOS) BuildAction(contractName, actionName, permissionLevel, data interf
 }
 
 func (m *EOS) ProposeMultiSig(proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, actions ...*eosc.Action) (*ProposeResponse, error) {
+	return m.ProposeMultiSigCtx(context.Background(), proposerName, requested, expireIn, actions...)
+}
+
+func (m *EOS) ProposeMultiSigCtx(ctx context.Context, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, actions ...*eosc.Action) (*ProposeResponse, error) {
 	proposer, err := util.ToAccountName(proposerName)
 	if err != nil {
 		return nil, err
 	}
 	proposalName := eosc.Name(util.RandAccountName())
-	transaction, err := m.BuildTrx(expireIn, actions...)
+	transaction, err := m.BuildTrxCtx(ctx, expireIn, actions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to propose multi sig, unable to build transaction, err: %v", err)
 	}
 	proposeAction := msig.NewPropose(proposer, proposalName, requested, transaction)
-	resp, err := m.Trx(proposeAction)
+	resp, err := m.TrxCtx(ctx, proposeAction)
 	if err != nil {
 		return nil, fmt.Errorf("failed pushing propose transaction, error: %v", err)
 	}
@@ -885,6 +1091,10 @@ func (m *EOS) ProposeMultiSig(proposerName interface{}, requested []eosc.Permiss
 }
 
 func (m *EOS) ApproveMultiSig(proposerName interface{}, proposalName interface{}, permissionLevel eosc.PermissionLevel) (*eosc.PushTransactionFullResp, error) {
+	return m.ApproveMultiSigCtx(context.Background(), proposerName, proposalName, permissionLevel)
+}
+
+func (m *EOS) ApproveMultiSigCtx(ctx context.Context, proposerName interface{}, proposalName interface{}, permissionLevel eosc.PermissionLevel) (*eosc.PushTransactionFullResp, error) {
 	proposer, err := util.ToAccountName(proposerName)
 	if err != nil {
 		return nil, err
@@ -895,7 +1105,7 @@ func (m *EOS) ApproveMultiSig(proposerName interface{}, proposalName interface{}
 	}
 
 	approveAction := msig.NewApprove(proposer, proposal, permissionLevel)
-	resp, err := m.Trx(approveAction)
+	resp, err := m.TrxCtx(ctx, approveAction)
 	if err != nil {
 		return nil, fmt.Errorf("failed pushing approve transaction, error: %v", err)
 	}
@@ -903,6 +1113,10 @@ func (m *EOS) ApproveMultiSig(proposerName interface{}, proposalName interface{}
 }
 
 func (m *EOS) ExecuteMultiSig(proposerName interface{}, proposalName interface{}, executerName interface{}) (*eosc.PushTransactionFullResp, error) {
+	return m.ExecuteMultiSigCtx(context.Background(), proposerName, proposalName, executerName)
+}
+
+func (m *EOS) ExecuteMultiSigCtx(ctx context.Context, proposerName interface{}, proposalName interface{}, executerName interface{}) (*eosc.PushTransactionFullResp, error) {
 	proposer, err := util.ToAccountName(proposerName)
 	if err != nil {
 		return nil, err
@@ -918,25 +1132,72 @@ func (m *EOS) ExecuteMultiSig(proposerName interface{}, proposalName interface{}
 	}
 
 	execAction := msig.NewExec(proposer, proposal, executer)
-	resp, err := m.Trx(execAction)
+	resp, err := m.TrxCtx(ctx, execAction)
 	if err != nil {
 		return nil, fmt.Errorf("failed pushing exec transaction, error: %v", err)
 	}
 	return resp, nil
 }
 
+// ProposeSetContractMultiSig proposes setting the contract, the ABI is not checked for breaking changes,
+// use ProposeSetContractMultiSigWithOpts for that
 func (m *EOS) ProposeSetContractMultiSig(proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}, wasmFile, abiFile string) (*ProposeResponse, error) {
+	return m.ProposeSetContractMultiSigCtx(context.Background(), proposerName, requested, expireIn, accountName, wasmFile, abiFile)
+}
 
+func (m *EOS) ProposeSetContractMultiSigCtx(ctx context.Context, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}, wasmFile, abiFile string) (*ProposeResponse, error) {
+	return m.ProposeSetContractMultiSigWithOptsCtx(ctx, proposerName, requested, expireIn, accountName, wasmFile, abiFile, &ProposeSetContractOpts{
+		AllowBreakingABI: true,
+	})
+}
+
+type ProposeSetContractOpts struct {
+	// AllowBreakingABI proposes the contract even if the new ABI has breaking changes
+	AllowBreakingABI bool
+}
+
+// ProposeSetContractMultiSigWithOpts proposes setting the contract like ProposeSetContractMultiSig, but fails with
+// a BreakingABIError without proposing anything if the new ABI has breaking changes, unless AllowBreakingABI is set,
+// nil opts are the same as the zero value
+func (m *EOS) ProposeSetContractMultiSigWithOpts(proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}, wasmFile, abiFile string, opts *ProposeSetContractOpts) (*ProposeResponse, error) {
+	return m.ProposeSetContractMultiSigWithOptsCtx(context.Background(), proposerName, requested, expireIn, accountName, wasmFile, abiFile, opts)
+}
+
+func (m *EOS) ProposeSetContractMultiSigWithOptsCtx(ctx context.Context, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}, wasmFile, abiFile string, opts *ProposeSetContractOpts) (*ProposeResponse, error) {
+	if opts == nil {
+		opts = &ProposeSetContractOpts{}
+	}
+	if !opts.AllowBreakingABI {
+		account, err := util.ToAccountName(accountName)
+		if err != nil {
+			return nil, err
+		}
+		diff, err := m.DiffContractABICtx(ctx, account, abiFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed checking abi of account: %v for breaking changes, error: %w", account, err)
+		}
+		if err := diff.BreakingABIError(account); err != nil {
+			return nil, err
+		}
+	}
 	actions, err := m.GetSetContractActions(accountName, wasmFile, abiFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed building set contract actions, error: %v", err)
 	}
-	return m.ProposeMultiSig(proposerName, requested, expireIn, actions...)
+	return m.ProposeMultiSigCtx(ctx, proposerName, requested, expireIn, actions...)
 
 }
 
 func (m *EOS) GetInfo() (*eosc.InfoResp, error) {
-	info, err := m.API.GetInfo(context.Background())
+	return m.GetInfoCtx(context.Background())
+}
+
+func (m *EOS) GetInfoCtx(ctx context.Context) (*eosc.InfoResp, error) {
+	var info *eosc.InfoResp
+	err := m.withRetries(ctx, func() (err error) {
+		info, err = m.API.GetInfo(ctx)
+		return
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed getting chain info, error: %v", err)
 	}
@@ -944,8 +1205,14 @@ func (m *EOS) GetInfo() (*eosc.InfoResp, error) {
 }
 
 func (m *EOS) GetActions(account eosc.AccountName, action eosc.ActionName, quantity int) ([]*dto.Action, error) {
-	time.Sleep(time.Second)
-	info, err := m.GetInfo()
+	return m.GetActionsCtx(context.Background(), account, action, quantity)
+}
+
+func (m *EOS) GetActionsCtx(ctx context.Context, account eosc.AccountName, action eosc.ActionName, quantity int) ([]*dto.Action, error) {
+	if err := sleepCtx(ctx, time.Second); err != nil {
+		return nil, err
+	}
+	info, err := m.GetInfoCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -958,9 +1225,12 @@ func (m *EOS) GetActions(account eosc.AccountName, action eosc.ActionName, quant
 			// fmt.Printf("Actions account: %v action:%v actions:%v \n", account, action, string(actstr))
 			return actions, nil
 		}
-		block, err := m.GetBlock(uint32(blockNum))
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := m.GetBlockCtx(ctx, uint32(blockNum))
 		if err != nil {
-			if !strings.Contains(err.Error(), "block trace missing") {
+			if !eoserr.IsBlockTraceMissing(err) {
 				return nil, err
 			}
 		}
@@ -975,7 +1245,11 @@ func (m *EOS) GetActions(account eosc.AccountName, action eosc.ActionName, quant
 }
 
 func (m *EOS) GetActionAt(account eosc.AccountName, action eosc.ActionName, pos int) (*dto.Action, error) {
-	actions, err := m.GetActions(account, action, pos+1)
+	return m.GetActionAtCtx(context.Background(), account, action, pos)
+}
+
+func (m *EOS) GetActionAtCtx(ctx context.Context, account eosc.AccountName, action eosc.ActionName, pos int) (*dto.Action, error) {
+	actions, err := m.GetActionsCtx(ctx, account, action, pos+1)
 	if err != nil {
 		return nil, err
 	}
@@ -985,10 +1259,16 @@ func (m *EOS) GetActionAt(account eosc.AccountName, action eosc.ActionName, pos
 	return nil, nil
 }
 
-func (m *EOS) GetBlock(blockNum uint32) (out *dto.Block, err error) {
-	err = m.API.Call(context.Background(), "trace_api", "get_block", M{"block_num": blockNum}, &out)
+func (m *EOS) GetBlock(blockNum uint32) (*dto.Block, error) {
+	return m.GetBlockCtx(context.Background(), blockNum)
+}
+
+func (m *EOS) GetBlockCtx(ctx context.Context, blockNum uint32) (out *dto.Block, err error) {
+	err = m.withRetries(ctx, func() error {
+		return m.API.Call(ctx, "trace_api", "get_block", M{"block_num": blockNum}, &out)
+	})
 	if err != nil {
-		err = fmt.Errorf("failed getting block: %v, error: %v", blockNum, err)
+		err = fmt.Errorf("failed getting block: %v, error: %w", blockNum, err)
 	}
 	return
 }
@@ -1,11 +1,18 @@
 package service_test
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
 	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
 	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go-toolbox/test"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"github.com/sebastianmontero/eos-go/system"
 	"gotest.tools/assert"
 )
 
@@ -23,6 +30,9 @@ func TestGetAccountNonExistantAccount(t *testing.T) {
 	eos := service.NewEOS(E.A)
 	accountName := "nonexistant"
 	accountData, err := eos.GetAccount(accountName)
+	assert.Assert(t, errors.Is(err, eoserr.ErrAccountNotFound))
+	assert.Assert(t, accountData == nil)
+	accountData, err = eos.FindAccount(accountName)
 	assert.NilError(t, err)
 	assert.Assert(t, accountData == nil)
 }
@@ -35,3 +45,402 @@ func TestGetBlock(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Assert(t, block.Number == 5)
 }
+
+func TestGetActionsCtxCanceled(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actions, err := eos.GetActionsCtx(ctx, "eosio", "newaccount", 1)
+	assert.Equal(t, err, context.Canceled)
+	assert.Assert(t, actions == nil)
+}
+
+func TestTrxAndWaitIrreversible(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	action := system.NewNewAccount("eosio", "waitfinal", *service.GetEOSIOPublicKey())
+	receipt, err := eos.TrxAndWait(service.NewWaitOpts(service.FinalityIrreversible), action)
+	assert.NilError(t, err)
+	assert.Equal(t, receipt.TransactionID, receipt.Resp.TransactionID)
+	assert.Assert(t, receipt.BlockNum > 0)
+	assert.Assert(t, receipt.Irreversible)
+	assert.Equal(t, receipt.Status, "executed")
+}
+
+func TestWaitForTrxExpired(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	info, err := eos.GetInfo()
+	assert.NilError(t, err)
+	opts := service.NewWaitOpts(service.FinalityIncluded)
+	opts.Expiration = info.HeadBlockTime.Time
+	// a transaction that was never pushed behaves like a dropped one
+	trxID := "0000000000000000000000000000000000000000000000000000000000000001"
+	start := time.Now()
+	receipt, err := eos.WaitForTrx(opts, trxID, info.HeadBlockNum)
+	assert.Assert(t, errors.Is(err, service.ErrTrxExpired), "error: %v", err)
+	assert.Equal(t, receipt.BlockNum, uint32(0))
+	assert.Assert(t, time.Since(start) < time.Minute)
+}
+
+func TestDryRun(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	action := system.NewNewAccount("eosio", "dryrun", *service.GetEOSIOPublicKey())
+	result, err := eos.DryRun(action)
+	assert.NilError(t, err)
+	assert.Assert(t, result.Succeeded())
+	assert.Assert(t, result.CPUUsageUS > 0)
+	assert.Equal(t, len(result.Trace.ActionTraces), 1)
+	accountData, err := eos.FindAccount("dryrun")
+	assert.NilError(t, err)
+	assert.Assert(t, accountData == nil)
+
+	action = system.NewNewAccount("eosio", "usera", *service.GetEOSIOPublicKey())
+	result, err = eos.DryRun(action)
+	assert.NilError(t, err)
+	assert.Assert(t, !result.Succeeded())
+	assert.Equal(t, result.Error.Code, eoserr.AccountNameExistsExceptionCode)
+}
+
+func TestDispatcher(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	dispatcher := service.NewDispatcher(eos, &service.DispatcherOpts{
+		Workers: 3,
+		TPS:     20,
+	})
+	dispatcher.Start(context.Background())
+	accounts := []string{"dispatcha", "dispatchb", "dispatchc", "dispatchd", "dispatche"}
+	go func() {
+		for _, account := range accounts {
+			_, err := dispatcher.Submit(context.Background(), system.NewNewAccount("eosio", eosc.AN(account), *service.GetEOSIOPublicKey()))
+			assert.NilError(t, err)
+		}
+		dispatcher.Close()
+	}()
+	seqs := make(map[uint64]bool)
+	for result := range dispatcher.Results() {
+		assert.NilError(t, result.Error)
+		assert.Assert(t, result.TransactionID != "")
+		seqs[result.Seq] = true
+	}
+	assert.Equal(t, len(seqs), len(accounts))
+	for _, account := range accounts {
+		_, err := eos.GetAccount(account)
+		assert.NilError(t, err)
+	}
+	_, err := dispatcher.Submit(context.Background(), system.NewNewAccount("eosio", "dispatchf", *service.GetEOSIOPublicKey()))
+	assert.Equal(t, err, service.ErrDispatcherClosed)
+}
+
+func TestBatchTrxResume(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	opts := &service.BatchOpts{
+		MaxActions: 2,
+		StateFile:  filepath.Join(t.TempDir(), "batches.json"),
+	}
+	actions := newAccountActions("resumea", "resumeb", "resumec")
+	report, err := eos.BatchTrx(opts, actions...)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Batches), 2)
+	assert.Equal(t, report.Count(service.BatchConfirmed), 2)
+
+	report, err = eos.BatchTrx(opts, actions...)
+	assert.NilError(t, err)
+	assert.Equal(t, report.Count(service.BatchSkipped), 2)
+}
+
+func TestOfflineTrx(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	action := system.NewNewAccount("eosio", "offline", *service.GetEOSIOPublicKey())
+	offlineTrx, err := eos.ExportTrx(time.Hour, action)
+	assert.NilError(t, err)
+	assert.Equal(t, len(offlineTrx.CandidateKeys), 1)
+	path := filepath.Join(t.TempDir(), "trx.json")
+	assert.NilError(t, offlineTrx.Save(path))
+
+	offlineTrx, err = service.LoadOfflineTrx(path)
+	assert.NilError(t, err)
+	keyBag := &eosc.KeyBag{}
+	assert.NilError(t, keyBag.ImportPrivateKey(context.Background(), service.EOSIOKey))
+	signed, err := offlineTrx.Sign(context.Background(), keyBag)
+	assert.NilError(t, err)
+	assert.Equal(t, signed, 1)
+	signed, err = offlineTrx.Sign(context.Background(), keyBag)
+	assert.NilError(t, err)
+	assert.Equal(t, signed, 0)
+	unsignedKeys, err := offlineTrx.UnsignedKeys()
+	assert.NilError(t, err)
+	assert.Equal(t, len(unsignedKeys), 0)
+	assert.NilError(t, eos.VerifyOfflineTrx(offlineTrx))
+
+	_, err = eos.PushOfflineTrx(offlineTrx)
+	assert.NilError(t, err)
+	_, err = eos.GetAccount("offline")
+	assert.NilError(t, err)
+}
+
+func TestOfflineTrxDelegatedAuthority(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	authority, err := service.NewAuthorityBuilder(1).Account("userb@active", 1).Build()
+	assert.NilError(t, err)
+	_, err = eos.UpdatePermission("usera", "active", "owner", authority)
+	assert.NilError(t, err)
+
+	action := system.NewNewAccount("usera", "offdelegate", *service.GetEOSIOPublicKey())
+	offlineTrx, err := eos.ExportTrx(time.Hour, action)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, offlineTrx.CandidateKeys, []ecc.PublicKey{*service.GetEOSIOPublicKey()})
+
+	keyBag := &eosc.KeyBag{}
+	assert.NilError(t, keyBag.ImportPrivateKey(context.Background(), service.EOSIOKey))
+	signed, err := offlineTrx.Sign(context.Background(), keyBag)
+	assert.NilError(t, err)
+	assert.Equal(t, signed, 1)
+	_, err = eos.PushOfflineTrx(offlineTrx)
+	assert.NilError(t, err)
+	_, err = eos.GetAccount("offdelegate")
+	assert.NilError(t, err)
+}
+
+func TestOfflineTrxMultiKeyThreshold(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	keys := make([]ecc.PrivateKey, 0, 3)
+	builder := service.NewAuthorityBuilder(2)
+	for i := 0; i < 3; i++ {
+		key, err := ecc.NewRandomPrivateKey()
+		assert.NilError(t, err)
+		keys = append(keys, *key)
+		builder.Key(key.PublicKey().String(), 1)
+	}
+	authority, err := builder.Build()
+	assert.NilError(t, err)
+	_, err = eos.UpdatePermission("usera", "active", "owner", authority)
+	assert.NilError(t, err)
+
+	action := system.NewNewAccount("usera", "offthresh", *service.GetEOSIOPublicKey())
+	offlineTrx, err := eos.ExportTrx(time.Hour, action)
+	assert.NilError(t, err)
+	assert.Equal(t, len(offlineTrx.CandidateKeys), 3)
+
+	// the first and third keys satisfy the threshold, whichever subset the node would pick
+	for i, key := range []ecc.PrivateKey{keys[0], keys[2]} {
+		keyBag := &eosc.KeyBag{}
+		assert.NilError(t, keyBag.ImportPrivateKey(context.Background(), key.String()))
+		signed, err := offlineTrx.Sign(context.Background(), keyBag)
+		assert.NilError(t, err)
+		assert.Equal(t, signed, 1)
+		if i == 0 {
+			err = eos.VerifyOfflineTrx(offlineTrx)
+			var unsatisfiedErr *eoserr.UnsatisfiedAuthError
+			assert.Assert(t, errors.As(err, &unsatisfiedErr))
+			assert.DeepEqual(t, unsatisfiedErr.Permissions, []string{"usera@active"})
+			_, err = eos.PushOfflineTrx(offlineTrx)
+			assert.Assert(t, eoserr.IsUnsatisfiedAuth(err))
+		}
+	}
+	unsignedKeys, err := offlineTrx.UnsignedKeys()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, unsignedKeys, []ecc.PublicKey{keys[1].PublicKey()})
+	assert.NilError(t, eos.VerifyOfflineTrx(offlineTrx))
+	_, err = eos.PushOfflineTrx(offlineTrx)
+	assert.NilError(t, err)
+	_, err = eos.GetAccount("offthresh")
+	assert.NilError(t, err)
+}
+
+func TestRequiredKeys(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	eos.RequiredKeysMode = service.RequiredKeysLocal
+	_, err := eos.AddEOSIOKey()
+	assert.NilError(t, err)
+	irrelevantKey, err := ecc.NewRandomPrivateKey()
+	assert.NilError(t, err)
+	_, err = eos.AddKey(irrelevantKey.String())
+	assert.NilError(t, err)
+
+	tx, err := eos.BuildTrx(time.Minute, system.NewNewAccount("eosio", "reqkeys", irrelevantKey.PublicKey()))
+	assert.NilError(t, err)
+	requiredKeys, err := eos.RequiredKeys(tx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, requiredKeys, []ecc.PublicKey{*service.GetEOSIOPublicKey()})
+
+	ownerKey, err := ecc.NewRandomPrivateKey()
+	assert.NilError(t, err)
+	ownerPublicKey := ownerKey.PublicKey()
+	_, err = eos.CreateAccount("reqkeys2", &ownerPublicKey, false)
+	assert.NilError(t, err)
+	tx, err = eos.BuildTrx(time.Minute, system.NewNewAccount("reqkeys2", "reqkeys3", irrelevantKey.PublicKey()))
+	assert.NilError(t, err)
+	_, err = eos.RequiredKeys(tx)
+	var unsatisfiedErr *eoserr.UnsatisfiedAuthError
+	assert.Assert(t, errors.As(err, &unsatisfiedErr))
+	assert.DeepEqual(t, unsatisfiedErr.Permissions, []string{"reqkeys2@active"})
+
+	eos.RequiredKeysMode = service.RequiredKeysChain
+	_, err = eos.RequiredKeys(tx)
+	assert.Assert(t, errors.As(err, &unsatisfiedErr))
+}
+
+func TestUpdateAndDeletePermission(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	authority, err := service.NewAuthorityBuilder(1).
+		Key(service.GetEOSIOPublicKey(), 1).
+		Account("usera@active", 1).
+		Build()
+	assert.NilError(t, err)
+	diff, err := eos.UpdatePermission("usera", "custom", "active", authority)
+	assert.NilError(t, err)
+	assert.Assert(t, !diff.IsEmpty())
+	permission, err := eos.GetAccountPermission("usera", "custom")
+	assert.NilError(t, err)
+	assert.Equal(t, permission.Parent, "active")
+
+	action, diff, err := eos.GetUpdatePermissionAction("usera", "custom", "active", authority)
+	assert.NilError(t, err)
+	assert.Assert(t, action == nil)
+	assert.Assert(t, diff.IsEmpty())
+
+	deleted, err := eos.DeletePermission("usera", "custom")
+	assert.NilError(t, err)
+	assert.Assert(t, deleted)
+	deleted, err = eos.DeletePermission("usera", "custom")
+	assert.NilError(t, err)
+	assert.Assert(t, !deleted)
+	_, err = eos.DeletePermission("usera", "active")
+	assert.ErrorContains(t, err, "can not be deleted")
+}
+
+func TestLinkAuth(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	authority, err := service.NewAuthorityBuilder(1).Account("usera@active", 1).Build()
+	assert.NilError(t, err)
+	_, err = eos.UpdatePermission("usera", "transfer", "active", authority)
+	assert.NilError(t, err)
+
+	linked, err := eos.LinkAuth("usera", "eosio.token", "transfer", "transfer")
+	assert.NilError(t, err)
+	assert.Assert(t, linked)
+	linked, err = eos.LinkAuth("usera", "eosio.token", "transfer", "transfer")
+	assert.NilError(t, err)
+	assert.Assert(t, !linked)
+	links, err := eos.GetLinks("usera")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, links, []*service.PermissionLink{{Code: "eosio.token", Action: "transfer", Permission: "transfer"}})
+
+	desired := []*service.PermissionLink{{Code: "eosio.token", Action: "", Permission: "transfer"}}
+	actions, err := eos.ReconcileLinks("usera", desired)
+	assert.NilError(t, err)
+	assert.Equal(t, len(actions), 2)
+	actions, err = eos.ReconcileLinks("usera", desired)
+	assert.NilError(t, err)
+	assert.Equal(t, len(actions), 0)
+
+	unlinked, err := eos.UnlinkAuth("usera", "eosio.token", "")
+	assert.NilError(t, err)
+	assert.Assert(t, unlinked)
+	unlinked, err = eos.UnlinkAuth("usera", "eosio.token", "")
+	assert.NilError(t, err)
+	assert.Assert(t, !unlinked)
+}
+
+func TestAuditPermissions(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	graph, err := eos.AuditPermissions("usera", 1)
+	assert.NilError(t, err)
+	assert.Assert(t, graph.Node("usera", "owner") != nil)
+	assert.Assert(t, graph.Node("usera", "active") != nil)
+	assert.Assert(t, len(graph.Findings) > 0)
+	_, err = eos.AuditPermissions("nonexistant", 1)
+	assert.ErrorContains(t, err, "does not exist")
+}
+
+func TestProvisionAccounts(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	publicKey := service.GetEOSIOPublicKey().String()
+	specs := []*service.AccountSpec{
+		{Name: "usera", OwnerKey: publicKey},
+		{Name: "provisiona", OwnerKey: publicKey},
+		{Name: "provisionb", OwnerKey: publicKey},
+	}
+	opts := &service.ProvisionOpts{
+		AccountsPerTrx: 2,
+		ResultsFile:    filepath.Join(t.TempDir(), "results.json"),
+	}
+	report, err := eos.ProvisionAccounts(specs, opts)
+	assert.NilError(t, err)
+	assert.Equal(t, report.Find("usera").Status, service.ProvisionStatusSkipped)
+	assert.Equal(t, report.Find("provisiona").Status, service.ProvisionStatusCreated)
+	assert.Assert(t, report.Find("provisiona").TransactionID != "")
+	assert.Equal(t, report.Find("provisiona").TransactionID, report.Find("provisionb").TransactionID)
+
+	resumed, err := eos.ProvisionAccounts(specs, opts)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, resumed, report)
+}
+
+func TestSetContractOnlyPushesChanges(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	dir := t.TempDir()
+	wasm, abi := test.WriteContract(t, dir, 0, "hi")
+
+	report, err := eos.SetContract("deploytest", wasm, abi, service.GetEOSIOPublicKey())
+	assert.NilError(t, err)
+	assert.Assert(t, report.CodeUpdated)
+	assert.Assert(t, report.ABIUpdated)
+	assert.Assert(t, report.TransactionID != "")
+
+	report, err = eos.SetContract("deploytest", wasm, abi, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !report.Updated())
+	assert.Equal(t, report.TransactionID, "")
+
+	_, abiWithBye := test.WriteContract(t, dir, 0, "hi", "bye")
+	report, err = eos.SetContract("deploytest", wasm, abiWithBye, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !report.CodeUpdated)
+	assert.Assert(t, report.ABIUpdated)
+	assert.Assert(t, report.ABIDiff != nil && !report.ABIDiff.IsBreaking())
+
+	newWasm, _ := test.WriteContract(t, dir, 1, "hi", "bye")
+	report, err = eos.SetContract("deploytest", newWasm, abiWithBye, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, report.CodeUpdated)
+	assert.Assert(t, !report.ABIUpdated)
+	assert.Assert(t, report.PreviousCodeHash != report.CodeHash)
+
+	var breakingErr *eoserr.BreakingABIError
+	_, err = eos.SetContractWithOpts("deploytest", newWasm, abi, nil)
+	assert.Assert(t, errors.As(err, &breakingErr))
+	_, err = eos.SetContractWithOpts("deploytest", newWasm, abi, &service.SetContractOpts{PublicKey: service.GetEOSIOPublicKey()})
+	assert.Assert(t, errors.As(err, &breakingErr))
+}
+
+func TestProposeSetContractMultiSigRejectsBreakingABI(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	dir := t.TempDir()
+	wasm, abi := test.WriteContract(t, dir, 0, "hi", "bye")
+	_, err := eos.SetContract("proposetest", wasm, abi, service.GetEOSIOPublicKey())
+	assert.NilError(t, err)
+
+	_, breakingABI := test.WriteContract(t, dir, 0, "hi")
+	requested := []eosc.PermissionLevel{{Actor: "usera", Permission: "active"}}
+	_, err = eos.ProposeSetContractMultiSigWithOpts("usera", requested, time.Hour, "proposetest", wasm, breakingABI, nil)
+	var breakingErr *eoserr.BreakingABIError
+	assert.Assert(t, errors.As(err, &breakingErr))
+	assert.Equal(t, breakingErr.Account, "proposetest")
+	assert.Equal(t, len(breakingErr.Changes), 2)
+}
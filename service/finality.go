@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/dto"
+)
+
+const (
+	defaultFinalityTimeout      = 5 * time.Minute
+	defaultFinalityPollInterval = 500 * time.Millisecond
+	// defaultTrxExpiration is the expiration eos-go sets on the transactions pushed by Trx
+	defaultTrxExpiration = 30 * time.Second
+)
+
+// ErrTrxExpired is returned when the head block time passes the expiration of the transaction
+// and it was not included in any block, so it was dropped or expired
+var ErrTrxExpired = errors.New("transaction expired without being included in a block")
+
+type Finality int
+
+const (
+	// FinalityIncluded waits until the transaction is included in a block
+	FinalityIncluded Finality = iota
+	// FinalityIrreversible waits until the block that includes the transaction is irreversible
+	FinalityIrreversible
+)
+
+func (m Finality) String() string {
+	switch m {
+	case FinalityIncluded:
+		return "included"
+	case FinalityIrreversible:
+		return "irreversible"
+	default:
+		return fmt.Sprintf("Finality(%d)", int(m))
+	}
+}
+
+type WaitOpts struct {
+	Finality     Finality
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Expiration of the transaction, if set the wait fails with ErrTrxExpired once the head block time
+	// passes it and the transaction is not included, TrxAndWait sets it
+	Expiration time.Time
+}
+
+func NewWaitOpts(finality Finality) *WaitOpts {
+	return &WaitOpts{
+		Finality:     finality,
+		Timeout:      defaultFinalityTimeout,
+		PollInterval: defaultFinalityPollInterval,
+	}
+}
+
+// TrxReceipt reports the block that included a transaction and its final status
+type TrxReceipt struct {
+	TransactionID string                        `json:"transaction_id"`
+	BlockNum      uint32                        `json:"block_num"`
+	BlockID       string                        `json:"block_id"`
+	Status        string                        `json:"status"`
+	Irreversible  bool                          `json:"irreversible"`
+	Resp          *eosc.PushTransactionFullResp `json:"-"`
+}
+
+func (m *TrxReceipt) String() string {
+	return fmt.Sprintf("TransactionID: %v, BlockNum: %v, Status: %v, Irreversible: %v", m.TransactionID, m.BlockNum, m.Status, m.Irreversible)
+}
+
+// TrxAndWait pushes the transaction and blocks until it reaches the finality specified in the options
+func (m *EOS) TrxAndWait(opts *WaitOpts, actions ...*eosc.Action) (*TrxReceipt, error) {
+	return m.TrxAndWaitCtx(context.Background(), opts, actions...)
+}
+
+func (m *EOS) TrxAndWaitCtx(ctx context.Context, opts *WaitOpts, actions ...*eosc.Action) (*TrxReceipt, error) {
+	info, err := m.GetInfoCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.TrxCtx(ctx, actions...)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = NewWaitOpts(FinalityIncluded)
+	}
+	if opts.Expiration.IsZero() {
+		// the transaction was built before it was pushed, so its expiration is not later than this
+		waitOpts := *opts
+		waitOpts.Expiration = time.Now().Add(defaultTrxExpiration)
+		opts = &waitOpts
+	}
+	receipt, err := m.WaitForTrxCtx(ctx, opts, resp.TransactionID, info.HeadBlockNum)
+	if receipt != nil {
+		receipt.Resp = resp
+	}
+	return receipt, err
+}
+
+// WaitForTrx blocks until the transaction with the specified id reaches the finality specified in the options,
+// the transaction is searched starting at fromBlockNum, if the options have the expiration of the transaction
+// it fails with ErrTrxExpired as soon as the transaction can no longer be included
+func (m *EOS) WaitForTrx(opts *WaitOpts, trxID string, fromBlockNum uint32) (*TrxReceipt, error) {
+	return m.WaitForTrxCtx(context.Background(), opts, trxID, fromBlockNum)
+}
+
+func (m *EOS) WaitForTrxCtx(ctx context.Context, opts *WaitOpts, trxID string, fromBlockNum uint32) (*TrxReceipt, error) {
+	if opts == nil {
+		opts = NewWaitOpts(FinalityIncluded)
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultFinalityTimeout
+	}
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultFinalityPollInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	receipt := &TrxReceipt{
+		TransactionID: trxID,
+	}
+	nextBlockNum := fromBlockNum
+	var lastErr error
+	for {
+		info, err := m.GetInfoCtx(ctx)
+		if err != nil {
+			lastErr = err
+		} else if receipt.BlockNum == 0 {
+			nextBlockNum, lastErr = m.findTrx(ctx, receipt, nextBlockNum, info.HeadBlockNum)
+			if receipt.BlockNum != 0 && opts.Finality == FinalityIncluded {
+				return receipt, nil
+			}
+			// every block up to the head was searched, and no later block can include an expired transaction
+			if receipt.BlockNum == 0 && nextBlockNum > info.HeadBlockNum && !opts.Expiration.IsZero() &&
+				info.HeadBlockTime.After(opts.Expiration) {
+				return receipt, fmt.Errorf("failed waiting for transaction: %v to be %v, expiration: %v, head block time: %v, error: %w",
+					trxID, opts.Finality, opts.Expiration.UTC(), info.HeadBlockTime.UTC(), ErrTrxExpired)
+			}
+		}
+		if receipt.BlockNum != 0 && info != nil && info.LastIrreversibleBlockNum >= receipt.BlockNum {
+			included, err := m.isTrxInBlock(ctx, receipt)
+			if err != nil {
+				lastErr = err
+			} else if included {
+				receipt.Irreversible = true
+				return receipt, nil
+			} else {
+				// the block that included the transaction was forked out, look for it again
+				nextBlockNum = receipt.BlockNum
+				receipt.BlockNum = 0
+				receipt.BlockID = ""
+				receipt.Status = ""
+			}
+		}
+		if err := sleepCtx(ctx, pollInterval); err != nil {
+			if lastErr != nil {
+				return receipt, fmt.Errorf("failed waiting for transaction: %v to be %v, last error: %v, error: %w", trxID, opts.Finality, lastErr, err)
+			}
+			return receipt, fmt.Errorf("failed waiting for transaction: %v to be %v, error: %w", trxID, opts.Finality, err)
+		}
+	}
+}
+
+// findTrx looks for the transaction in the blocks from fromBlockNum to toBlockNum, filling in the
+// receipt if found, and returns the block number the search should continue from
+func (m *EOS) findTrx(ctx context.Context, receipt *TrxReceipt, fromBlockNum, toBlockNum uint32) (uint32, error) {
+	for blockNum := fromBlockNum; blockNum <= toBlockNum; blockNum++ {
+		block, err := m.GetBlockCtx(ctx, blockNum)
+		if err != nil {
+			// the trace api may lag behind the head block, try again on the next poll
+			return blockNum, err
+		}
+		if trx := findBlockTrx(block, receipt.TransactionID); trx != nil {
+			receipt.BlockNum = block.Number
+			receipt.BlockID = block.ID.String()
+			receipt.Status = trx.Status
+			return blockNum, nil
+		}
+	}
+	return toBlockNum + 1, nil
+}
+
+func (m *EOS) isTrxInBlock(ctx context.Context, receipt *TrxReceipt) (bool, error) {
+	block, err := m.GetBlockCtx(ctx, receipt.BlockNum)
+	if err != nil {
+		return false, err
+	}
+	if block.ID.String() != receipt.BlockID {
+		return false, nil
+	}
+	trx := findBlockTrx(block, receipt.TransactionID)
+	if trx == nil {
+		return false, nil
+	}
+	receipt.Status = trx.Status
+	return true, nil
+}
+
+func findBlockTrx(block *dto.Block, trxID string) *dto.Transaction {
+	if block == nil {
+		return nil
+	}
+	for _, trx := range block.Transactions {
+		if trx.ID.String() == trxID {
+			return trx
+		}
+	}
+	return nil
+}
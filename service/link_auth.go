@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/system"
+)
+
+// PermissionLink is a link between a contract action and the permission of an account required to call it,
+// an empty Action is the wildcard that links all the actions of the contract
+type PermissionLink struct {
+	Code       eosc.AccountName    `json:"code" yaml:"code"`
+	Action     eosc.ActionName     `json:"action" yaml:"action"`
+	Permission eosc.PermissionName `json:"permission" yaml:"permission"`
+}
+
+func (m *PermissionLink) String() string {
+	action := m.Action
+	if action == "" {
+		action = "*"
+	}
+	return fmt.Sprintf("%v::%v -> %v", m.Code, action, m.Permission)
+}
+
+type linkKey struct {
+	code   eosc.AccountName
+	action eosc.ActionName
+}
+
+type accountLinksResp struct {
+	Permissions []struct {
+		PermName      string `json:"perm_name"`
+		LinkedActions []struct {
+			Account eosc.AccountName `json:"account"`
+			Action  eosc.ActionName  `json:"action"`
+		} `json:"linked_actions"`
+	} `json:"permissions"`
+}
+
+// GetLinks returns the links of the account, sorted by code and action, it relies on the
+// linked_actions returned by get_account which are only available on recent versions of nodeos
+func (m *EOS) GetLinks(accountName interface{}) ([]*PermissionLink, error) {
+	return m.GetLinksCtx(context.Background(), accountName)
+}
+
+func (m *EOS) GetLinksCtx(ctx context.Context, accountName interface{}) ([]*PermissionLink, error) {
+	acct, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	var resp accountLinksResp
+	err = m.withRetries(ctx, func() error {
+		return m.API.Call(ctx, "chain", "get_account", M{"account_name": acct}, &resp)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed getting links of account: %v, error: %w", acct, err)
+	}
+	links := make([]*PermissionLink, 0)
+	for _, permission := range resp.Permissions {
+		for _, linkedAction := range permission.LinkedActions {
+			links = append(links, &PermissionLink{
+				Code:       linkedAction.Account,
+				Action:     linkedAction.Action,
+				Permission: eosc.PermissionName(permission.PermName),
+			})
+		}
+	}
+	sortLinks(links)
+	return links, nil
+}
+
+// FindLink returns the permission linked to the contract action, or nil if there is no link
+func (m *EOS) FindLink(accountName, codeName, actionName interface{}) (*PermissionLink, error) {
+	return m.FindLinkCtx(context.Background(), accountName, codeName, actionName)
+}
+
+func (m *EOS) FindLinkCtx(ctx context.Context, accountName, codeName, actionName interface{}) (*PermissionLink, error) {
+	code, err := util.ToAccountName(codeName)
+	if err != nil {
+		return nil, err
+	}
+	action, err := util.ToActionName(actionName)
+	if err != nil {
+		return nil, err
+	}
+	links, err := m.GetLinksCtx(ctx, accountName)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if link.Code == code && link.Action == action {
+			return link, nil
+		}
+	}
+	return nil, nil
+}
+
+// LinkAuth links the action of the code account to the permission of the account, actionName can
+// be "" to link all the actions of the code, returns false if the link already existed
+func (m *EOS) LinkAuth(accountName, codeName, actionName, permissionName interface{}) (bool, error) {
+	return m.LinkAuthCtx(context.Background(), accountName, codeName, actionName, permissionName)
+}
+
+func (m *EOS) LinkAuthCtx(ctx context.Context, accountName, codeName, actionName, permissionName interface{}) (bool, error) {
+	action, err := m.GetLinkAuthActionCtx(ctx, accountName, codeName, actionName, permissionName)
+	if err != nil {
+		return false, err
+	}
+	if action == nil {
+		return false, nil
+	}
+	_, err = m.TrxCtx(ctx, action)
+	if err != nil {
+		return false, fmt.Errorf("error linking permission: %v, to action %v:%v, error: %w", permissionName, codeName, actionName, err)
+	}
+	return true, nil
+}
+
+func (m *EOS) ProposeLinkAuth(proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName, codeName, actionName, permissionName interface{}) (*ProposeResponse, error) {
+	return m.ProposeLinkAuthCtx(context.Background(), proposerName, requested, expireIn, accountName, codeName, actionName, permissionName)
+}
+
+// ProposeLinkAuthCtx proposes the linkauth, returns nil if the link already exists
+func (m *EOS) ProposeLinkAuthCtx(ctx context.Context, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName, codeName, actionName, permissionName interface{}) (*ProposeResponse, error) {
+	action, err := m.GetLinkAuthActionCtx(ctx, accountName, codeName, actionName, permissionName)
+	if err != nil {
+		return nil, err
+	}
+	if action == nil {
+		return nil, nil
+	}
+	response, err := m.ProposeMultiSigCtx(ctx, proposerName, requested, expireIn, action)
+	if err != nil {
+		return nil, fmt.Errorf("error proposing link of permission: %v, to action %v:%v, error: %w", permissionName, codeName, actionName, err)
+	}
+	return response, nil
+}
+
+func (m *EOS) GetLinkAuthAction(accountName, codeName, actionName, permissionName interface{}) (*eosc.Action, error) {
+	return m.GetLinkAuthActionCtx(context.Background(), accountName, codeName, actionName, permissionName)
+}
+
+// GetLinkAuthActionCtx returns the linkauth action, or nil if the link already exists
+func (m *EOS) GetLinkAuthActionCtx(ctx context.Context, accountName, codeName, actionName, permissionName interface{}) (*eosc.Action, error) {
+	acct, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	code, err := util.ToAccountName(codeName)
+	if err != nil {
+		return nil, err
+	}
+	action, err := util.ToActionName(actionName)
+	if err != nil {
+		return nil, err
+	}
+	permission, err := util.ToPermissionName(permissionName)
+	if err != nil {
+		return nil, err
+	}
+	link, err := m.FindLinkCtx(ctx, acct, code, action)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil && link.Permission == permission {
+		return nil, nil
+	}
+	return system.NewLinkAuth(acct, code, action, permission), nil
+}
+
+// UnlinkAuth removes the link of the action of the code account, returns false if there was no link
+func (m *EOS) UnlinkAuth(accountName, codeName, actionName interface{}) (bool, error) {
+	return m.UnlinkAuthCtx(context.Background(), accountName, codeName, actionName)
+}
+
+func (m *EOS) UnlinkAuthCtx(ctx context.Context, accountName, codeName, actionName interface{}) (bool, error) {
+	action, err := m.GetUnlinkAuthActionCtx(ctx, accountName, codeName, actionName)
+	if err != nil {
+		return false, err
+	}
+	if action == nil {
+		return false, nil
+	}
+	_, err = m.TrxCtx(ctx, action)
+	if err != nil {
+		return false, fmt.Errorf("error unlinking action %v:%v, error: %w", codeName, actionName, err)
+	}
+	return true, nil
+}
+
+func (m *EOS) ProposeUnlinkAuth(proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName, codeName, actionName interface{}) (*ProposeResponse, error) {
+	return m.ProposeUnlinkAuthCtx(context.Background(), proposerName, requested, expireIn, accountName, codeName, actionName)
+}
+
+// ProposeUnlinkAuthCtx proposes the unlinkauth, returns nil if there is no link
+func (m *EOS) ProposeUnlinkAuthCtx(ctx context.Context, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName, codeName, actionName interface{}) (*ProposeResponse, error) {
+	action, err := m.GetUnlinkAuthActionCtx(ctx, accountName, codeName, actionName)
+	if err != nil {
+		return nil, err
+	}
+	if action == nil {
+		return nil, nil
+	}
+	response, err := m.ProposeMultiSigCtx(ctx, proposerName, requested, expireIn, action)
+	if err != nil {
+		return nil, fmt.Errorf("error proposing unlink of action %v:%v, error: %w", codeName, actionName, err)
+	}
+	return response, nil
+}
+
+func (m *EOS) GetUnlinkAuthAction(accountName, codeName, actionName interface{}) (*eosc.Action, error) {
+	return m.GetUnlinkAuthActionCtx(context.Background(), accountName, codeName, actionName)
+}
+
+// GetUnlinkAuthActionCtx returns the unlinkauth action, or nil if there is no link
+func (m *EOS) GetUnlinkAuthActionCtx(ctx context.Context, accountName, codeName, actionName interface{}) (*eosc.Action, error) {
+	acct, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	link, err := m.FindLinkCtx(ctx, acct, codeName, actionName)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, nil
+	}
+	return system.NewUnlinkAuth(acct, link.Code, link.Action), nil
+}
+
+// ReconcileLinks makes the links of the account match the desired ones, links that are missing or
+// point to another permission are linked and links that are not desired are unlinked, all in one
+// transaction, returns the actions pushed, which are empty if the links already match
+func (m *EOS) ReconcileLinks(accountName interface{}, desired []*PermissionLink) ([]*eosc.Action, error) {
+	return m.ReconcileLinksCtx(context.Background(), accountName, desired)
+}
+
+func (m *EOS) ReconcileLinksCtx(ctx context.Context, accountName interface{}, desired []*PermissionLink) ([]*eosc.Action, error) {
+	actions, err := m.GetReconcileLinksActionsCtx(ctx, accountName, desired)
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		return actions, nil
+	}
+	_, err = m.TrxCtx(ctx, actions...)
+	if err != nil {
+		return nil, fmt.Errorf("error reconciling links of account: %v, error: %w", accountName, err)
+	}
+	return actions, nil
+}
+
+func (m *EOS) ProposeReconcileLinks(proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}, desired []*PermissionLink) (*ProposeResponse, error) {
+	return m.ProposeReconcileLinksCtx(context.Background(), proposerName, requested, expireIn, accountName, desired)
+}
+
+// ProposeReconcileLinksCtx proposes the actions to reconcile the links, returns nil if the links already match
+func (m *EOS) ProposeReconcileLinksCtx(ctx context.Context, proposerName interface{}, requested []eosc.PermissionLevel, expireIn time.Duration, accountName interface{}, desired []*PermissionLink) (*ProposeResponse, error) {
+	actions, err := m.GetReconcileLinksActionsCtx(ctx, accountName, desired)
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	response, err := m.ProposeMultiSigCtx(ctx, proposerName, requested, expireIn, actions...)
+	if err != nil {
+		return nil, fmt.Errorf("error proposing reconcile of links of account: %v, error: %w", accountName, err)
+	}
+	return response, nil
+}
+
+func (m *EOS) GetReconcileLinksActions(accountName interface{}, desired []*PermissionLink) ([]*eosc.Action, error) {
+	return m.GetReconcileLinksActionsCtx(context.Background(), accountName, desired)
+}
+
+// GetReconcileLinksActionsCtx returns the unlinkauth actions of the links that are not desired followed
+// by the linkauth actions of the desired links that are missing or point to another permission
+func (m *EOS) GetReconcileLinksActionsCtx(ctx context.Context, accountName interface{}, desired []*PermissionLink) ([]*eosc.Action, error) {
+	acct, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	desiredLinks := make(map[linkKey]*PermissionLink, len(desired))
+	for _, link := range desired {
+		key := linkKey{code: link.Code, action: link.Action}
+		if existing, ok := desiredLinks[key]; ok && existing.Permission != link.Permission {
+			return nil, fmt.Errorf("failed reconciling links, action %v:%v is linked to more than one permission", link.Code, link.Action)
+		}
+		desiredLinks[key] = link
+	}
+	current, err := m.GetLinksCtx(ctx, acct)
+	if err != nil {
+		return nil, err
+	}
+	currentLinks := make(map[linkKey]*PermissionLink, len(current))
+	actions := make([]*eosc.Action, 0)
+	for _, link := range current {
+		key := linkKey{code: link.Code, action: link.Action}
+		currentLinks[key] = link
+		if _, ok := desiredLinks[key]; !ok {
+			actions = append(actions, system.NewUnlinkAuth(acct, link.Code, link.Action))
+		}
+	}
+	sorted := make([]*PermissionLink, 0, len(desiredLinks))
+	for _, link := range desiredLinks {
+		sorted = append(sorted, link)
+	}
+	sortLinks(sorted)
+	for _, link := range sorted {
+		existing, ok := currentLinks[linkKey{code: link.Code, action: link.Action}]
+		if !ok || existing.Permission != link.Permission {
+			actions = append(actions, system.NewLinkAuth(acct, link.Code, link.Action, link.Permission))
+		}
+	}
+	return actions, nil
+}
+
+func sortLinks(links []*PermissionLink) {
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].Code != links[j].Code {
+			return links[i].Code < links[j].Code
+		}
+		return links[i].Action < links[j].Action
+	})
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go/ecc"
+)
+
+// OfflineTrx is a transaction exported to be signed by offline signers and broadcast later,
+// the packed transaction is the source of truth, the decoded transaction is informational
+type OfflineTrx struct {
+	ChainID eosc.Checksum256 `json:"chain_id"`
+	// CandidateKeys are the keys that can take part in authorizing the transaction, any subset of
+	// them that satisfies the thresholds of the permissions is enough
+	CandidateKeys []ecc.PublicKey         `json:"candidate_keys"`
+	Packed        *eosc.PackedTransaction `json:"packed"`
+	Transaction   *eosc.SignedTransaction `json:"transaction,omitempty"`
+}
+
+func (m *OfflineTrx) String() string {
+	id, _ := m.ID()
+	return fmt.Sprintf("TransactionID: %v, ChainID: %v, Signatures: %v, CandidateKeys: %v", id, m.ChainID, len(m.Packed.Signatures), len(m.CandidateKeys))
+}
+
+// ID returns the transaction id, it does not change when signatures are added
+func (m *OfflineTrx) ID() (eosc.Checksum256, error) {
+	return m.Packed.ID()
+}
+
+// PackedHex returns the hex encoded packed transaction, without signatures
+func (m *OfflineTrx) PackedHex() string {
+	return hex.EncodeToString(m.Packed.PackedTransaction)
+}
+
+func (m *OfflineTrx) digest() ([]byte, error) {
+	signedTx, err := m.Packed.Unpack()
+	if err != nil {
+		return nil, fmt.Errorf("failed unpacking transaction, error: %v", err)
+	}
+	txdata, cfd, err := signedTx.PackedTransactionAndCFD()
+	if err != nil {
+		return nil, fmt.Errorf("failed packing transaction, error: %v", err)
+	}
+	return eosc.SigDigest(m.ChainID, txdata, cfd), nil
+}
+
+// SignedKeys returns the public keys that have signed the transaction
+func (m *OfflineTrx) SignedKeys() ([]ecc.PublicKey, error) {
+	digest, err := m.digest()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]ecc.PublicKey, 0, len(m.Packed.Signatures))
+	for _, signature := range m.Packed.Signatures {
+		key, err := signature.PublicKey(digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed recovering public key from signature: %v, error: %v", signature, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// UnsignedKeys returns the candidate keys that have not signed the transaction yet, not all of
+// them have to sign, use EOS.VerifyOfflineTrx to check if the signatures are enough
+func (m *OfflineTrx) UnsignedKeys() ([]ecc.PublicKey, error) {
+	signedKeys, err := m.SignedKeys()
+	if err != nil {
+		return nil, err
+	}
+	return keysDifference(m.CandidateKeys, signedKeys), nil
+}
+
+// Sign adds the signatures of the candidate keys available to the signer that have not signed
+// yet, returns the number of signatures added, it does not require a connection to the chain
+func (m *OfflineTrx) Sign(ctx context.Context, signer eosc.Signer) (int, error) {
+	availableKeys, err := signer.AvailableKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed getting signer available keys, error: %v", err)
+	}
+	signedKeys, err := m.SignedKeys()
+	if err != nil {
+		return 0, err
+	}
+	keys := keysDifference(availableKeys, signedKeys)
+	if len(m.CandidateKeys) > 0 {
+		keys = keysIntersection(keys, m.CandidateKeys)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	signedTx, err := m.Packed.Unpack()
+	if err != nil {
+		return 0, fmt.Errorf("failed unpacking transaction, error: %v", err)
+	}
+	signedTx.Signatures = nil
+	signedTx, err = signer.Sign(ctx, signedTx, m.ChainID, keys...)
+	if err != nil {
+		return 0, fmt.Errorf("failed signing transaction, error: %v", err)
+	}
+	m.Packed.Signatures = append(m.Packed.Signatures, signedTx.Signatures...)
+	m.syncSignatures()
+	return len(signedTx.Signatures), nil
+}
+
+// Merge adds the signatures of other, that must be the same transaction, that are not already present
+func (m *OfflineTrx) Merge(other *OfflineTrx) error {
+	if !bytes.Equal(m.ChainID, other.ChainID) || !bytes.Equal(m.Packed.PackedTransaction, other.Packed.PackedTransaction) {
+		return fmt.Errorf("failed merging signatures, the transactions are different")
+	}
+	for _, signature := range other.Packed.Signatures {
+		if !hasSignature(m.Packed.Signatures, signature) {
+			m.Packed.Signatures = append(m.Packed.Signatures, signature)
+		}
+	}
+	m.syncSignatures()
+	return nil
+}
+
+func (m *OfflineTrx) syncSignatures() {
+	if m.Transaction != nil {
+		m.Transaction.Signatures = m.Packed.Signatures
+	}
+}
+
+func (m *OfflineTrx) Save(path string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed marshalling offline transaction, error: %v", err)
+	}
+	err = ioutil.WriteFile(path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed writing offline transaction to: %v, error: %v", path, err)
+	}
+	return nil
+}
+
+func LoadOfflineTrx(path string) (*OfflineTrx, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading offline transaction from: %v, error: %v", path, err)
+	}
+	offlineTrx := &OfflineTrx{}
+	err = json.Unmarshal(content, offlineTrx)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling offline transaction from: %v, error: %v", path, err)
+	}
+	if offlineTrx.Packed == nil {
+		return nil, fmt.Errorf("invalid offline transaction file: %v, packed transaction is missing", path)
+	}
+	// the decoded transaction is replaced so it can not differ from what is signed
+	offlineTrx.Transaction, err = offlineTrx.Packed.Unpack()
+	if err != nil {
+		return nil, fmt.Errorf("failed unpacking offline transaction from: %v, error: %v", path, err)
+	}
+	return offlineTrx, nil
+}
+
+// ExportTrx builds a transaction to be signed offline, the candidate keys are the keys of the
+// permissions of the authorizations, fails if they can not satisfy the authorizations
+func (m *EOS) ExportTrx(expireIn time.Duration, actions ...*eosc.Action) (*OfflineTrx, error) {
+	return m.ExportTrxCtx(context.Background(), expireIn, actions...)
+}
+
+func (m *EOS) ExportTrxCtx(ctx context.Context, expireIn time.Duration, actions ...*eosc.Action) (*OfflineTrx, error) {
+	txOpts, err := m.txOptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting txOptions to export trx, error: %v", err)
+	}
+	tx := eosc.NewTransaction(actions, txOpts)
+	if expireIn > 0 {
+		tx.SetExpiration(expireIn)
+	}
+	candidateKeys, err := m.resolveCandidateKeys(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	signedTx := eosc.NewSignedTransaction(tx)
+	packed, err := signedTx.Pack(eosc.CompressionNone)
+	if err != nil {
+		return nil, fmt.Errorf("failed packing transaction to export, error: %v", err)
+	}
+	return &OfflineTrx{
+		ChainID:       txOpts.ChainID,
+		CandidateKeys: candidateKeys,
+		Packed:        packed,
+		Transaction:   signedTx,
+	}, nil
+}
+
+// resolveCandidateKeys returns the keys of the permissions used by the transaction, including the keys
+// of the account permissions their authorities delegate to, and checks with get_required_keys that
+// they can satisfy the transaction
+func (m *EOS) resolveCandidateKeys(ctx context.Context, tx *eosc.Transaction) ([]ecc.PublicKey, error) {
+	resolver := newAuthorityResolver(m, nil)
+	candidateKeys := make([]ecc.PublicKey, 0)
+	seen := make(map[eosc.PermissionLevel]bool)
+	for _, action := range trxActions(tx) {
+		for _, auth := range action.Authorization {
+			if seen[auth] {
+				continue
+			}
+			seen[auth] = true
+			authority, err := resolver.authority(ctx, auth)
+			if err != nil {
+				return nil, fmt.Errorf("failed getting permission: %v to resolve required keys, error: %w", auth, err)
+			}
+			if authority == nil {
+				return nil, fmt.Errorf("failed resolving required keys, error: %w: %v@%v", eoserr.ErrPermissionNotFound, auth.Actor, auth.Permission)
+			}
+			keys, err := resolver.candidateKeys(ctx, auth, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed getting keys of permission: %v to resolve required keys, error: %w", auth, err)
+			}
+			candidateKeys = append(candidateKeys, keysDifference(keys, candidateKeys)...)
+		}
+	}
+	if _, err := m.chainRequiredKeys(ctx, tx, candidateKeys); err != nil {
+		return nil, err
+	}
+	return candidateKeys, nil
+}
+
+// VerifyOfflineTrx returns nil if the signatures of the transaction satisfy its authorizations,
+// otherwise an eoserr.UnsatisfiedAuthError listing the permissions that are not satisfied yet
+func (m *EOS) VerifyOfflineTrx(offlineTrx *OfflineTrx) error {
+	return m.VerifyOfflineTrxCtx(context.Background(), offlineTrx)
+}
+
+func (m *EOS) VerifyOfflineTrxCtx(ctx context.Context, offlineTrx *OfflineTrx) error {
+	signedKeys, err := offlineTrx.SignedKeys()
+	if err != nil {
+		return err
+	}
+	signedTx, err := offlineTrx.Packed.Unpack()
+	if err != nil {
+		return fmt.Errorf("failed unpacking offline transaction, error: %v", err)
+	}
+	_, err = m.chainRequiredKeys(ctx, signedTx.Transaction, signedKeys)
+	return err
+}
+
+// PushOfflineTrx broadcasts a transaction signed offline
+func (m *EOS) PushOfflineTrx(offlineTrx *OfflineTrx) (*eosc.PushTransactionFullResp, error) {
+	return m.PushOfflineTrxCtx(context.Background(), offlineTrx)
+}
+
+func (m *EOS) PushOfflineTrxCtx(ctx context.Context, offlineTrx *OfflineTrx) (*eosc.PushTransactionFullResp, error) {
+	info, err := m.GetInfoCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(info.ChainID, offlineTrx.ChainID) {
+		return nil, fmt.Errorf("failed pushing offline transaction, it is for chain: %v, but the node is on chain: %v", offlineTrx.ChainID, info.ChainID)
+	}
+	if err := m.VerifyOfflineTrxCtx(ctx, offlineTrx); err != nil {
+		return nil, fmt.Errorf("failed pushing offline transaction, missing signatures, error: %w", err)
+	}
+	var resp *eosc.PushTransactionFullResp
+	err = m.withRetries(ctx, func() (err error) {
+		resp, err = m.API.PushTransaction(ctx, offlineTrx.Packed)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed pushing offline transaction, error: %w", err)
+	}
+	return resp, nil
+}
+
+func hasSignature(signatures []ecc.Signature, signature ecc.Signature) bool {
+	for _, s := range signatures {
+		if s.String() == signature.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func keysDifference(keys, remove []ecc.PublicKey) []ecc.PublicKey {
+	removeSet := make(map[string]bool, len(remove))
+	for _, key := range remove {
+		removeSet[key.String()] = true
+	}
+	diff := make([]ecc.PublicKey, 0)
+	for _, key := range keys {
+		if !removeSet[key.String()] {
+			diff = append(diff, key)
+		}
+	}
+	return diff
+}
+
+func keysIntersection(keys, other []ecc.PublicKey) []ecc.PublicKey {
+	otherSet := make(map[string]bool, len(other))
+	for _, key := range other {
+		otherSet[key.String()] = true
+	}
+	intersection := make([]ecc.PublicKey, 0)
+	for _, key := range keys {
+		if otherSet[key.String()] {
+			intersection = append(intersection, key)
+		}
+	}
+	return intersection
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/system"
+)
+
+// UpdatePermission creates or updates the permission so that it has the authority and parent,
+// updateauth is only pushed if they differ from the ones on chain, returns the authority diff
+func (m *EOS) UpdatePermission(accountName, permissionName, parentName interface{}, authority *eosc.Authority) (*AuthorityDiff, error) {
+	return m.UpdatePermissionCtx(context.Background(), accountName, permissionName, parentName, authority)
+}
+
+func (m *EOS) UpdatePermissionCtx(ctx context.Context, accountName, permissionName, parentName interface{}, authority *eosc.Authority) (*AuthorityDiff, error) {
+	action, diff, err := m.GetUpdatePermissionActionCtx(ctx, accountName, permissionName, parentName, authority)
+	if err != nil {
+		return nil, err
+	}
+	if action == nil {
+		return diff, nil
+	}
+	_, err = m.TrxCtx(ctx, action)
+	if err != nil {
+		return nil, fmt.Errorf("error updating permission: %v@%v, error: %w", accountName, permissionName, err)
+	}
+	return diff, nil
+}
+
+func (m *EOS) GetUpdatePermissionAction(accountName, permissionName, parentName interface{}, authority *eosc.Authority) (*eosc.Action, *AuthorityDiff, error) {
+	return m.GetUpdatePermissionActionCtx(context.Background(), accountName, permissionName, parentName, authority)
+}
+
+// GetUpdatePermissionActionCtx returns the updateauth action, or nil if the permission already has the authority
+// and parent, the action is authorized by the permission itself or by its parent if it does not exist yet
+func (m *EOS) GetUpdatePermissionActionCtx(ctx context.Context, accountName, permissionName, parentName interface{}, authority *eosc.Authority) (*eosc.Action, *AuthorityDiff, error) {
+	acct, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, nil, err
+	}
+	permission, err := util.ToPermissionName(permissionName)
+	if err != nil {
+		return nil, nil, err
+	}
+	parent, err := util.ToPermissionName(parentName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if permission == "owner" {
+		parent = ""
+	}
+	desired := CopyAuthority(authority)
+	SortAuthority(desired)
+	if err := ValidateAuthority(desired); err != nil {
+		return nil, nil, fmt.Errorf("failed updating permission: %v@%v, error: %v", acct, permission, err)
+	}
+	current, err := m.FindAccountPermissionCtx(ctx, acct, string(permission))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed getting permission: %v@%v, error: %w", acct, permission, err)
+	}
+	usingPermission := parent
+	var currentAuthority *eosc.Authority
+	if current != nil {
+		usingPermission = permission
+		currentAuthority = &current.RequiredAuth
+	}
+	diff := DiffAuthority(currentAuthority, desired)
+	if current != nil && diff.IsEmpty() && current.Parent == string(parent) {
+		return nil, diff, nil
+	}
+	return system.NewUpdateAuth(acct, permission, parent, *desired, usingPermission), diff, nil
+}
+
+// DeletePermission deletes the permission if it exists, returns true if it was deleted, the
+// permission must not have children nor linked actions
+func (m *EOS) DeletePermission(accountName, permissionName interface{}) (bool, error) {
+	return m.DeletePermissionCtx(context.Background(), accountName, permissionName)
+}
+
+func (m *EOS) DeletePermissionCtx(ctx context.Context, accountName, permissionName interface{}) (bool, error) {
+	action, err := m.GetDeletePermissionActionCtx(ctx, accountName, permissionName)
+	if err != nil {
+		return false, err
+	}
+	if action == nil {
+		return false, nil
+	}
+	_, err = m.TrxCtx(ctx, action)
+	if err != nil {
+		return false, fmt.Errorf("error deleting permission: %v@%v, error: %w", accountName, permissionName, err)
+	}
+	return true, nil
+}
+
+func (m *EOS) GetDeletePermissionAction(accountName, permissionName interface{}) (*eosc.Action, error) {
+	return m.GetDeletePermissionActionCtx(context.Background(), accountName, permissionName)
+}
+
+// GetDeletePermissionActionCtx returns the deleteauth action, or nil if the permission does not exist,
+// the action is authorized by the permission itself
+func (m *EOS) GetDeletePermissionActionCtx(ctx context.Context, accountName, permissionName interface{}) (*eosc.Action, error) {
+	acct, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	permission, err := util.ToPermissionName(permissionName)
+	if err != nil {
+		return nil, err
+	}
+	if permission == "owner" || permission == "active" {
+		return nil, fmt.Errorf("failed deleting permission: %v@%v, the owner and active permissions can not be deleted", acct, permission)
+	}
+	current, err := m.FindAccountPermissionCtx(ctx, acct, string(permission))
+	if err != nil {
+		return nil, fmt.Errorf("failed getting permission: %v@%v, error: %w", acct, permission, err)
+	}
+	if current == nil {
+		return nil, nil
+	}
+	action := system.NewDeleteAuth(acct, permission)
+	action.Authorization = []eosc.PermissionLevel{{Actor: acct, Permission: permission}}
+	return action, nil
+}
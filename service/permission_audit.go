@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+)
+
+type AuditSeverity string
+
+const (
+	AuditSeverityHigh   AuditSeverity = "high"
+	AuditSeverityMedium AuditSeverity = "medium"
+	AuditSeverityLow    AuditSeverity = "low"
+)
+
+// Audit rules
+const (
+	AuditRuleSingleKeyOwner    = "single-key-owner"
+	AuditRuleEOSIOCodeOnOwner  = "eosio-code-on-owner"
+	AuditRuleEOSIOCodeOnActive = "eosio-code-on-active"
+	AuditRuleOwnerEqualsActive = "owner-equals-active"
+	AuditRuleUnsatisfiable     = "unsatisfiable-threshold"
+	AuditRuleMissingAccount    = "missing-account"
+)
+
+// PermissionNode is a permission of an account in the permission graph
+type PermissionNode struct {
+	Account    eosc.AccountName    `json:"account"`
+	Permission eosc.PermissionName `json:"permission"`
+	Parent     eosc.PermissionName `json:"parent,omitempty"`
+	Threshold  uint32              `json:"threshold"`
+	Keys       []eosc.KeyWeight    `json:"keys"`
+	Waits      []eosc.WaitWeight   `json:"waits"`
+	// Links are the contract actions linked to the permission
+	Links []*PermissionLink `json:"links"`
+}
+
+func (m *PermissionNode) ID() string {
+	return fmt.Sprintf("%v@%v", m.Account, m.Permission)
+}
+
+// PermissionEdge is an account permission in the authority of From that can satisfy it with Weight
+type PermissionEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Weight uint16 `json:"weight"`
+}
+
+type AuditFinding struct {
+	Severity   AuditSeverity `json:"severity"`
+	Rule       string        `json:"rule"`
+	Permission string        `json:"permission"`
+	Message    string        `json:"message"`
+}
+
+func (m *AuditFinding) String() string {
+	return fmt.Sprintf("[%v] %v: %v", m.Severity, m.Permission, m.Message)
+}
+
+// PermissionGraph is the graph of the permissions of an account and of the accounts in their authorities
+type PermissionGraph struct {
+	Root  eosc.AccountName  `json:"root"`
+	Depth int               `json:"depth"`
+	Nodes []*PermissionNode `json:"nodes"`
+	Edges []*PermissionEdge `json:"edges"`
+	// Missing are the accounts referenced by authorities that do not exist
+	Missing []eosc.AccountName `json:"missing"`
+	// Truncated are the accounts referenced by authorities that were not walked because of the depth
+	Truncated []eosc.AccountName `json:"truncated"`
+	Findings  []*AuditFinding    `json:"findings"`
+}
+
+// AuditPermissions walks the permissions of the account and recursively of the accounts in their
+// authorities up to depth, and flags risky setups
+func (m *EOS) AuditPermissions(accountName interface{}, depth int) (*PermissionGraph, error) {
+	return m.AuditPermissionsCtx(context.Background(), accountName, depth)
+}
+
+func (m *EOS) AuditPermissionsCtx(ctx context.Context, accountName interface{}, depth int) (*PermissionGraph, error) {
+	root, err := util.ToAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	graph := &PermissionGraph{
+		Root:      root,
+		Depth:     depth,
+		Nodes:     make([]*PermissionNode, 0),
+		Edges:     make([]*PermissionEdge, 0),
+		Missing:   make([]eosc.AccountName, 0),
+		Truncated: make([]eosc.AccountName, 0),
+	}
+	visited := map[eosc.AccountName]bool{root: true}
+	level := []eosc.AccountName{root}
+	for currentDepth := 0; len(level) > 0; currentDepth++ {
+		next := make([]eosc.AccountName, 0)
+		for _, acct := range level {
+			account, err := m.FindAccountCtx(ctx, acct)
+			if err != nil {
+				return nil, fmt.Errorf("failed auditing permissions of account: %v, error: %w", acct, err)
+			}
+			if account == nil {
+				if acct == root {
+					return nil, fmt.Errorf("failed auditing permissions, account: %v does not exist", root)
+				}
+				graph.Missing = append(graph.Missing, acct)
+				continue
+			}
+			links, err := m.GetLinksCtx(ctx, acct)
+			if err != nil {
+				return nil, fmt.Errorf("failed auditing links of account: %v, error: %w", acct, err)
+			}
+			for _, referenced := range graph.addAccount(account, links) {
+				if visited[referenced] {
+					continue
+				}
+				visited[referenced] = true
+				if currentDepth >= depth {
+					graph.Truncated = append(graph.Truncated, referenced)
+					continue
+				}
+				next = append(next, referenced)
+			}
+		}
+		level = next
+	}
+	graph.Audit()
+	return graph, nil
+}
+
+// addAccount adds the permissions of the account and returns the accounts referenced by their authorities
+func (m *PermissionGraph) addAccount(account *eosc.AccountResp, links []*PermissionLink) []eosc.AccountName {
+	referenced := make([]eosc.AccountName, 0)
+	for _, permission := range account.Permissions {
+		node := &PermissionNode{
+			Account:    account.AccountName,
+			Permission: eosc.PermissionName(permission.PermName),
+			Parent:     eosc.PermissionName(permission.Parent),
+			Threshold:  permission.RequiredAuth.Threshold,
+			Keys:       permission.RequiredAuth.Keys,
+			Waits:      permission.RequiredAuth.Waits,
+			Links:      make([]*PermissionLink, 0),
+		}
+		for _, link := range links {
+			if link.Permission == node.Permission {
+				node.Links = append(node.Links, link)
+			}
+		}
+		m.Nodes = append(m.Nodes, node)
+		for _, level := range permission.RequiredAuth.Accounts {
+			m.Edges = append(m.Edges, &PermissionEdge{
+				From:   node.ID(),
+				To:     fmt.Sprintf("%v@%v", level.Permission.Actor, level.Permission.Permission),
+				Weight: level.Weight,
+			})
+			if level.Permission.Permission != eosioCodePermission {
+				referenced = append(referenced, level.Permission.Actor)
+			}
+		}
+	}
+	return referenced
+}
+
+// Audit evaluates the audit rules and sets the findings, sorted by severity
+func (m *PermissionGraph) Audit() []*AuditFinding {
+	findings := make([]*AuditFinding, 0)
+	add := func(severity AuditSeverity, rule, permission, message string, args ...interface{}) {
+		findings = append(findings, &AuditFinding{
+			Severity:   severity,
+			Rule:       rule,
+			Permission: permission,
+			Message:    fmt.Sprintf(message, args...),
+		})
+	}
+	for _, node := range m.Nodes {
+		edges := m.EdgesFrom(node.ID())
+		var totalWeight uint32
+		for _, key := range node.Keys {
+			totalWeight += uint32(key.Weight)
+			if node.Permission == "owner" && uint32(key.Weight) >= node.Threshold {
+				add(AuditSeverityHigh, AuditRuleSingleKeyOwner, node.ID(), "key: %v can satisfy the owner permission on its own", key.PublicKey)
+			}
+		}
+		for _, wait := range node.Waits {
+			totalWeight += uint32(wait.Weight)
+		}
+		for _, edge := range edges {
+			totalWeight += uint32(edge.Weight)
+			if edge.To != fmt.Sprintf("%v@%v", node.Account, eosioCodePermission) {
+				continue
+			}
+			switch node.Permission {
+			case "owner":
+				add(AuditSeverityHigh, AuditRuleEOSIOCodeOnOwner, node.ID(), "the contract of the account can act with the owner permission")
+			case "active":
+				add(AuditSeverityLow, AuditRuleEOSIOCodeOnActive, node.ID(), "the contract of the account can act with the active permission")
+			}
+		}
+		if totalWeight < node.Threshold {
+			add(AuditSeverityHigh, AuditRuleUnsatisfiable, node.ID(), "the total weight: %v is lower than the threshold: %v", totalWeight, node.Threshold)
+		}
+		if node.Permission == "owner" {
+			active := m.Node(node.Account, "active")
+			if active != nil && sameAuthority(node, active, edges, m.EdgesFrom(active.ID())) {
+				add(AuditSeverityMedium, AuditRuleOwnerEqualsActive, node.ID(), "the owner permission has the same authority as the active permission")
+			}
+		}
+	}
+	for _, acct := range m.Missing {
+		for _, edge := range m.Edges {
+			if strings.HasPrefix(edge.To, string(acct)+"@") {
+				add(AuditSeverityHigh, AuditRuleMissingAccount, edge.From, "the account: %v in the authority does not exist and could be created by anyone", acct)
+			}
+		}
+	}
+	severityOrder := map[AuditSeverity]int{AuditSeverityHigh: 0, AuditSeverityMedium: 1, AuditSeverityLow: 2}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityOrder[findings[i].Severity] < severityOrder[findings[j].Severity]
+	})
+	m.Findings = findings
+	return findings
+}
+
+func (m *PermissionGraph) Node(account eosc.AccountName, permission eosc.PermissionName) *PermissionNode {
+	for _, node := range m.Nodes {
+		if node.Account == account && node.Permission == permission {
+			return node
+		}
+	}
+	return nil
+}
+
+func (m *PermissionGraph) EdgesFrom(id string) []*PermissionEdge {
+	edges := make([]*PermissionEdge, 0)
+	for _, edge := range m.Edges {
+		if edge.From == id {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+func (m *PermissionGraph) JSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// DOT returns the graph in Graphviz DOT format, parent relations are dashed, authority relations
+// are labeled with their weight, and the permissions with high severity findings are red
+func (m *PermissionGraph) DOT() string {
+	risky := make(map[string]bool)
+	for _, finding := range m.Findings {
+		if finding.Severity == AuditSeverityHigh {
+			risky[finding.Permission] = true
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", string(m.Root))
+	b.WriteString("  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range m.Nodes {
+		label := []string{node.ID(), fmt.Sprintf("threshold: %v", node.Threshold)}
+		for _, key := range node.Keys {
+			label = append(label, fmt.Sprintf("%v (%v)", key.PublicKey, key.Weight))
+		}
+		for _, wait := range node.Waits {
+			label = append(label, fmt.Sprintf("wait %vs (%v)", wait.WaitSec, wait.Weight))
+		}
+		attrs := ""
+		if risky[node.ID()] {
+			attrs = ", color=red"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q%v];\n", node.ID(), strings.Join(label, "\n"), attrs)
+		if node.Parent != "" {
+			fmt.Fprintf(&b, "  %q -> %q [style=dashed, label=\"parent\"];\n", fmt.Sprintf("%v@%v", node.Account, node.Parent), node.ID())
+		}
+		for _, link := range node.Links {
+			linkID := link.String()
+			fmt.Fprintf(&b, "  %q [shape=note];\n", linkID)
+			fmt.Fprintf(&b, "  %q -> %q [style=dotted, label=\"linkauth\"];\n", linkID, node.ID())
+		}
+	}
+	for _, edge := range m.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=\"%v\"];\n", edge.From, edge.To, edge.Weight)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func sameAuthority(a, b *PermissionNode, aEdges, bEdges []*PermissionEdge) bool {
+	if a.Threshold != b.Threshold || len(a.Keys) != len(b.Keys) || len(aEdges) != len(bEdges) || len(a.Waits) != len(b.Waits) {
+		return false
+	}
+	keys := make(map[string]bool, len(a.Keys))
+	for _, key := range a.Keys {
+		keys[keyWeightID(key)] = true
+	}
+	for _, key := range b.Keys {
+		if !keys[keyWeightID(key)] {
+			return false
+		}
+	}
+	edges := make(map[PermissionEdge]bool, len(aEdges))
+	for _, edge := range aEdges {
+		edges[PermissionEdge{To: edge.To, Weight: edge.Weight}] = true
+	}
+	for _, edge := range bEdges {
+		if !edges[PermissionEdge{To: edge.To, Weight: edge.Weight}] {
+			return false
+		}
+	}
+	return true
+}
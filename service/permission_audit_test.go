@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func findingRules(findings []*service.AuditFinding) []string {
+	rules := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		rules = append(rules, finding.Rule+" "+finding.Permission)
+	}
+	return rules
+}
+
+func TestPermissionGraphAudit(t *testing.T) {
+	key := newTestPublicKey(t)
+	graph := &service.PermissionGraph{
+		Root: "usera",
+		Nodes: []*service.PermissionNode{
+			{Account: "usera", Permission: "owner", Threshold: 1, Keys: []eosc.KeyWeight{{PublicKey: key, Weight: 1}}},
+			{Account: "usera", Permission: "active", Parent: "owner", Threshold: 1, Keys: []eosc.KeyWeight{{PublicKey: key, Weight: 1}}},
+			{Account: "usera", Permission: "custom", Parent: "active", Threshold: 3, Keys: []eosc.KeyWeight{{PublicKey: key, Weight: 1}},
+				Links: []*service.PermissionLink{{Code: "eosio.token", Action: "transfer", Permission: "custom"}}},
+		},
+		Edges: []*service.PermissionEdge{
+			{From: "usera@owner", To: "usera@eosio.code", Weight: 1},
+			{From: "usera@custom", To: "ghost@active", Weight: 1},
+		},
+		Missing: []eosc.AccountName{"ghost"},
+	}
+	findings := graph.Audit()
+	assert.DeepEqual(t, findingRules(findings), []string{
+		"single-key-owner usera@owner",
+		"eosio-code-on-owner usera@owner",
+		"unsatisfiable-threshold usera@custom",
+		"missing-account usera@custom",
+	})
+
+	content, err := graph.JSON()
+	assert.NilError(t, err)
+	decoded := &service.PermissionGraph{}
+	assert.NilError(t, json.Unmarshal(content, decoded))
+	assert.Equal(t, len(decoded.Findings), 4)
+
+	dot := graph.DOT()
+	assert.Assert(t, strings.HasPrefix(dot, "digraph \"usera\" {"))
+	assert.Assert(t, strings.Contains(dot, "\"usera@owner\" -> \"usera@active\" [style=dashed"))
+	assert.Assert(t, strings.Contains(dot, "\"usera@custom\" -> \"ghost@active\" [label=\"1\"]"))
+	assert.Assert(t, strings.Contains(dot, "\"eosio.token::transfer -> custom\" -> \"usera@custom\""))
+	assert.Assert(t, strings.Contains(dot, "color=red"))
+}
+
+func TestPermissionGraphOwnerEqualsActive(t *testing.T) {
+	key := newTestPublicKey(t)
+	graph := &service.PermissionGraph{
+		Root: "usera",
+		Nodes: []*service.PermissionNode{
+			{Account: "usera", Permission: "owner", Threshold: 2, Keys: []eosc.KeyWeight{{PublicKey: key, Weight: 1}}},
+			{Account: "usera", Permission: "active", Parent: "owner", Threshold: 2, Keys: []eosc.KeyWeight{{PublicKey: key, Weight: 1}}},
+		},
+		Edges: []*service.PermissionEdge{
+			{From: "usera@owner", To: "userb@active", Weight: 1},
+			{From: "usera@active", To: "userb@active", Weight: 1},
+		},
+	}
+	assert.DeepEqual(t, findingRules(graph.Audit()), []string{"owner-equals-active usera@owner"})
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+)
+
+// PowerupFracScale is the value of a fraction that represents 100% of the resource
+const PowerupFracScale = 1e15
+
+// PowerupArgs are the arguments of the eosio.system powerup action
+type PowerupArgs struct {
+	Payer      eosc.AccountName `json:"payer"`
+	Receiver   eosc.AccountName `json:"receiver"`
+	Days       uint32           `json:"days"`
+	NetFrac    int64            `json:"net_frac"`
+	CPUFrac    int64            `json:"cpu_frac"`
+	MaxPayment eosc.Asset       `json:"max_payment"`
+}
+
+type PowerupStateResource struct {
+	Version              uint8         `json:"version"`
+	Weight               eosc.Int64    `json:"weight"`
+	WeightRatio          eosc.Int64    `json:"weight_ratio"`
+	AssumedStakeWeight   eosc.Int64    `json:"assumed_stake_weight"`
+	InitialWeightRatio   eosc.Int64    `json:"initial_weight_ratio"`
+	TargetWeightRatio    eosc.Int64    `json:"target_weight_ratio"`
+	InitialTimestamp     eosc.JSONTime `json:"initial_timestamp"`
+	TargetTimestamp      eosc.JSONTime `json:"target_timestamp"`
+	Exponent             eosc.Float64  `json:"exponent"`
+	DecaySecs            uint32        `json:"decay_secs"`
+	MinPrice             eosc.Asset    `json:"min_price"`
+	MaxPrice             eosc.Asset    `json:"max_price"`
+	Utilization          eosc.Int64    `json:"utilization"`
+	AdjustedUtilization  eosc.Int64    `json:"adjusted_utilization"`
+	UtilizationTimestamp eosc.JSONTime `json:"utilization_timestamp"`
+}
+
+// PowerupState is a row of the eosio powup.state table
+type PowerupState struct {
+	Version       uint8                 `json:"version"`
+	Net           *PowerupStateResource `json:"net"`
+	CPU           *PowerupStateResource `json:"cpu"`
+	PowerupDays   uint32                `json:"powerup_days"`
+	MinPowerupFee eosc.Asset            `json:"min_powerup_fee"`
+}
+
+// Fee calculates the fee for powering up the specified fractions of NET and CPU, it mirrors
+// the calculation done by the powerup action at the specified time
+func (m *PowerupState) Fee(netFrac, cpuFrac int64, now time.Time) (eosc.Asset, error) {
+	if netFrac < 0 || netFrac > PowerupFracScale || cpuFrac < 0 || cpuFrac > PowerupFracScale {
+		return eosc.Asset{}, fmt.Errorf("invalid powerup fractions, net: %v, cpu: %v, must be between 0 and %v", netFrac, cpuFrac, int64(PowerupFracScale))
+	}
+	netFee, err := m.Net.fee(netFrac, now)
+	if err != nil {
+		return eosc.Asset{}, fmt.Errorf("failed calculating net fee, error: %v", err)
+	}
+	cpuFee, err := m.CPU.fee(cpuFrac, now)
+	if err != nil {
+		return eosc.Asset{}, fmt.Errorf("failed calculating cpu fee, error: %v", err)
+	}
+	fee := eosc.Asset{
+		Amount: eosc.Int64(netFee + cpuFee),
+		Symbol: m.MinPowerupFee.Symbol,
+	}
+	if fee.Amount < m.MinPowerupFee.Amount {
+		return eosc.Asset{}, fmt.Errorf("powerup fee: %v is below the min powerup fee: %v, request bigger fractions", fee, m.MinPowerupFee)
+	}
+	return fee, nil
+}
+
+func (m *PowerupStateResource) fee(frac int64, now time.Time) (int64, error) {
+	if frac == 0 {
+		return 0, nil
+	}
+	if m.Weight <= 0 {
+		return 0, fmt.Errorf("powerup resource has no weight")
+	}
+	utilizationIncrease := int64(float64(frac) * float64(m.Weight) / PowerupFracScale)
+	if utilizationIncrease <= 0 {
+		return 0, fmt.Errorf("fraction: %v is too small", frac)
+	}
+	if int64(m.Utilization)+utilizationIncrease > int64(m.Weight) {
+		return 0, fmt.Errorf("not enough resources available to powerup")
+	}
+	utilization := float64(m.Utilization)
+	adjustedUtilization := m.adjustedUtilization(now)
+	weight := float64(m.Weight)
+	minPrice := float64(m.MinPrice.Amount)
+	maxPrice := float64(m.MaxPrice.Amount)
+	exponent := float64(m.Exponent)
+
+	priceFunction := func(utilization float64) float64 {
+		if exponent-1 <= 0 {
+			return maxPrice
+		}
+		return minPrice + (maxPrice-minPrice)*math.Pow(utilization/weight, exponent-1)
+	}
+	priceIntegralDelta := func(start, end float64) float64 {
+		coefficient := (maxPrice - minPrice) / exponent
+		startU := start / weight
+		endU := end / weight
+		return minPrice*endU - minPrice*startU + coefficient*(math.Pow(endU, exponent)-math.Pow(startU, exponent))
+	}
+
+	fee := 0.0
+	start := utilization
+	end := start + float64(utilizationIncrease)
+	if start < adjustedUtilization {
+		fee += priceFunction(adjustedUtilization) * math.Min(float64(utilizationIncrease), adjustedUtilization-start) / weight
+		start = adjustedUtilization
+	}
+	if start < end {
+		fee += priceIntegralDelta(start, end)
+	}
+	return int64(math.Ceil(fee)), nil
+}
+
+// adjustedUtilization decays the adjusted utilization to the specified time
+func (m *PowerupStateResource) adjustedUtilization(now time.Time) float64 {
+	utilization := float64(m.Utilization)
+	adjusted := float64(m.AdjustedUtilization)
+	elapsed := now.Sub(m.UtilizationTimestamp.Time)
+	if elapsed <= 0 || m.DecaySecs == 0 {
+		return adjusted
+	}
+	if utilization >= adjusted {
+		return utilization
+	}
+	return utilization + (adjusted-utilization)*math.Exp(-elapsed.Seconds()/float64(m.DecaySecs))
+}
+
+// PowerupResult reports the powerup done and the resulting resource limits of the receiver
+type PowerupResult struct {
+	Fee        eosc.Asset
+	MaxPayment eosc.Asset
+	Resp       *eosc.PushTransactionFullResp
+	CPULimit   eosc.AccountResourceLimit
+	NetLimit   eosc.AccountResourceLimit
+}
+
+func (m *PowerupResult) String() string {
+	return fmt.Sprintf("Fee: %v, MaxPayment: %v, CPU: %v/%v, NET: %v/%v", m.Fee, m.MaxPayment, m.CPULimit.Available, m.CPULimit.Max, m.NetLimit.Available, m.NetLimit.Max)
+}
+
+func (m *EOS) GetPowerupState() (*PowerupState, error) {
+	return m.GetPowerupStateCtx(context.Background())
+}
+
+func (m *EOS) GetPowerupStateCtx(ctx context.Context) (*PowerupState, error) {
+	var states []*PowerupState
+	err := m.GetTableRowsCtx(ctx, eosc.GetTableRowsRequest{
+		Code:  "eosio",
+		Scope: "",
+		Table: "powup.state",
+		Limit: 1,
+	}, &states)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting powerup state, error: %v", err)
+	}
+	if len(states) == 0 || states[0].Net == nil || states[0].CPU == nil {
+		return nil, fmt.Errorf("failed getting powerup state, powerup is not configured")
+	}
+	return states[0], nil
+}
+
+// PowerupFee calculates the fee for powering up the specified fractions of NET and CPU, fractions
+// are relative to PowerupFracScale
+func (m *EOS) PowerupFee(netFrac, cpuFrac int64) (eosc.Asset, error) {
+	return m.PowerupFeeCtx(context.Background(), netFrac, cpuFrac)
+}
+
+func (m *EOS) PowerupFeeCtx(ctx context.Context, netFrac, cpuFrac int64) (eosc.Asset, error) {
+	state, err := m.GetPowerupStateCtx(ctx)
+	if err != nil {
+		return eosc.Asset{}, err
+	}
+	return state.Fee(netFrac, cpuFrac, time.Now())
+}
+
+func (m *EOS) BuildPowerupAction(payerName, receiverName interface{}, days uint32, netFrac, cpuFrac int64, maxPayment eosc.Asset) (*eosc.Action, error) {
+	payer, err := util.ToAccountName(payerName)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := util.ToAccountName(receiverName)
+	if err != nil {
+		return nil, err
+	}
+	return m.BuildAction("eosio", "powerup", payer, &PowerupArgs{
+		Payer:      payer,
+		Receiver:   receiver,
+		Days:       days,
+		NetFrac:    netFrac,
+		CPUFrac:    cpuFrac,
+		MaxPayment: maxPayment,
+	})
+}
+
+// Powerup rents the specified fractions of NET and CPU for the receiver, fails without pushing
+// the powerup action if the calculated fee is greater than maxPayment
+func (m *EOS) Powerup(payer, receiver interface{}, netFrac, cpuFrac int64, maxPayment eosc.Asset) (*PowerupResult, error) {
+	return m.PowerupCtx(context.Background(), payer, receiver, netFrac, cpuFrac, maxPayment)
+}
+
+func (m *EOS) PowerupCtx(ctx context.Context, payer, receiver interface{}, netFrac, cpuFrac int64, maxPayment eosc.Asset) (*PowerupResult, error) {
+	state, err := m.GetPowerupStateCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := state.Fee(netFrac, cpuFrac, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed calculating powerup fee, error: %v", err)
+	}
+	if fee.Amount > maxPayment.Amount {
+		return nil, fmt.Errorf("powerup fee: %v is greater than max payment: %v", fee, maxPayment)
+	}
+	action, err := m.BuildPowerupAction(payer, receiver, state.PowerupDays, netFrac, cpuFrac, maxPayment)
+	if err != nil {
+		return nil, fmt.Errorf("failed building powerup action, error: %v", err)
+	}
+	resp, err := m.TrxCtx(ctx, action)
+	if err != nil {
+		return nil, fmt.Errorf("failed pushing powerup action, error: %w", err)
+	}
+	account, err := m.GetAccountCtx(ctx, receiver)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting account limits after powerup, error: %w", err)
+	}
+	return &PowerupResult{
+		Fee:        fee,
+		MaxPayment: maxPayment,
+		Resp:       resp,
+		CPULimit:   account.CPULimit,
+		NetLimit:   account.NetLimit,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func newPowerupStateResource() *service.PowerupStateResource {
+	return &service.PowerupStateResource{
+		Weight:    1000000000000,
+		Exponent:  2,
+		DecaySecs: 86400,
+		MinPrice:  eosAsset(10000),
+		MaxPrice:  eosAsset(1000000),
+	}
+}
+
+func TestPowerupFee(t *testing.T) {
+	state := &service.PowerupState{
+		Net:           newPowerupStateResource(),
+		CPU:           newPowerupStateResource(),
+		PowerupDays:   1,
+		MinPowerupFee: eosAsset(1),
+	}
+	// 1% of cpu: min_price * 0.01 + (max_price - min_price) / 2 * 0.01^2
+	fee, err := state.Fee(0, service.PowerupFracScale/100, time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, fee.Amount, eosc.Int64(150))
+
+	fee, err = state.Fee(service.PowerupFracScale/100, service.PowerupFracScale/100, time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, fee.Amount, eosc.Int64(300))
+
+	state.CPU.Utilization = state.CPU.Weight
+	_, err = state.Fee(0, service.PowerupFracScale/100, time.Now())
+	assert.ErrorContains(t, err, "not enough resources")
+
+	state.MinPowerupFee = eosAsset(1000)
+	_, err = state.Fee(service.PowerupFracScale/100, 0, time.Now())
+	assert.ErrorContains(t, err, "below the min powerup fee")
+}
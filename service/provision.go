@@ -0,0 +1,571 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"github.com/sebastianmontero/eos-go/system"
+	"gopkg.in/yaml.v2"
+)
+
+const defaultAccountsPerTrx = 10
+
+// PermissionSpec is a permission to create for a provisioned account, each key and account
+// has weight 1, accounts are in actor@permission format
+type PermissionSpec struct {
+	Name      string   `json:"name" yaml:"name"`
+	Parent    string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Threshold uint32   `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Keys      []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Accounts  []string `json:"accounts,omitempty" yaml:"accounts,omitempty"`
+}
+
+// AccountSpec describes an account to provision, assets are strings e.g. "1.0000 EOS"
+type AccountSpec struct {
+	Name      string `json:"name" yaml:"name"`
+	OwnerKey  string `json:"owner_key" yaml:"owner_key"`
+	ActiveKey string `json:"active_key,omitempty" yaml:"active_key,omitempty"`
+	RAMBytes  uint32 `json:"ram_bytes,omitempty" yaml:"ram_bytes,omitempty"`
+	NetStake  string `json:"net_stake,omitempty" yaml:"net_stake,omitempty"`
+	CPUStake  string `json:"cpu_stake,omitempty" yaml:"cpu_stake,omitempty"`
+	// Transfer is the quantity of tokens transferred from the creator to the new account
+	Transfer string `json:"transfer,omitempty" yaml:"transfer,omitempty"`
+	Memo     string `json:"memo,omitempty" yaml:"memo,omitempty"`
+	// Permissions are created with updateauth authorized by the active permission of the new account,
+	// in a transaction pushed once the account exists, so the signer must hold its active key
+	Permissions []*PermissionSpec `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+}
+
+type accountSpecsFile struct {
+	Accounts []*AccountSpec `yaml:"accounts"`
+}
+
+// LoadAccountSpecs reads the account specs from a csv, yaml or yml file
+func LoadAccountSpecs(path string) ([]*AccountSpec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening account specs file: %v, error: %v", path, err)
+	}
+	defer file.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadAccountSpecsCSV(file)
+	case ".yaml", ".yml":
+		return ReadAccountSpecsYAML(file)
+	default:
+		return nil, fmt.Errorf("unsupported account specs file: %v, it must be csv or yaml", path)
+	}
+}
+
+// ReadAccountSpecsYAML reads the specs from a yaml document with an accounts list
+func ReadAccountSpecsYAML(r io.Reader) ([]*AccountSpec, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading account specs, error: %v", err)
+	}
+	specsFile := &accountSpecsFile{}
+	err = yaml.UnmarshalStrict(content, specsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling account specs, error: %v", err)
+	}
+	return specsFile.Accounts, validateAccountSpecs(specsFile.Accounts)
+}
+
+// ReadAccountSpecsCSV reads the specs from a csv with a header row, the supported columns are name, owner_key,
+// active_key, ram_bytes, net_stake, cpu_stake, transfer, memo and permissions, permissions have the format
+// name=member|member;name=member where members are keys or actor@permission, their parent is active
+func ReadAccountSpecsCSV(r io.Reader) ([]*AccountSpec, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed reading account specs csv, error: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("failed reading account specs csv, the header row is missing")
+	}
+	columns := make(map[string]int, len(rows[0]))
+	for i, column := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("failed reading account specs csv, the name column is missing")
+	}
+	specs := make([]*AccountSpec, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		value := func(column string) string {
+			if pos, ok := columns[column]; ok && pos < len(row) {
+				return strings.TrimSpace(row[pos])
+			}
+			return ""
+		}
+		spec := &AccountSpec{
+			Name:      value("name"),
+			OwnerKey:  value("owner_key"),
+			ActiveKey: value("active_key"),
+			NetStake:  value("net_stake"),
+			CPUStake:  value("cpu_stake"),
+			Transfer:  value("transfer"),
+			Memo:      value("memo"),
+		}
+		if ramBytes := value("ram_bytes"); ramBytes != "" {
+			bytes, err := strconv.ParseUint(ramBytes, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("failed reading account specs csv, invalid ram_bytes: %v in row: %v", ramBytes, i+2)
+			}
+			spec.RAMBytes = uint32(bytes)
+		}
+		if permissions := value("permissions"); permissions != "" {
+			spec.Permissions, err = parsePermissionSpecs(permissions)
+			if err != nil {
+				return nil, fmt.Errorf("failed reading account specs csv, row: %v, error: %v", i+2, err)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, validateAccountSpecs(specs)
+}
+
+func parsePermissionSpecs(value string) ([]*PermissionSpec, error) {
+	permissions := make([]*PermissionSpec, 0)
+	for _, entry := range strings.Split(value, ";") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid permission: %v, expected name=member|member", entry)
+		}
+		permission := &PermissionSpec{Name: strings.TrimSpace(parts[0])}
+		for _, member := range strings.Split(parts[1], "|") {
+			member = strings.TrimSpace(member)
+			if strings.Contains(member, "@") {
+				permission.Accounts = append(permission.Accounts, member)
+			} else {
+				permission.Keys = append(permission.Keys, member)
+			}
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
+
+func validateAccountSpecs(specs []*AccountSpec) error {
+	names := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return fmt.Errorf("invalid account spec, name is required")
+		}
+		if names[spec.Name] {
+			return fmt.Errorf("invalid account spec, account: %v is duplicated", spec.Name)
+		}
+		names[spec.Name] = true
+		if spec.OwnerKey == "" {
+			return fmt.Errorf("invalid account spec: %v, owner_key is required", spec.Name)
+		}
+	}
+	return nil
+}
+
+type ProvisionStatus string
+
+const (
+	ProvisionStatusCreated ProvisionStatus = "created"
+	ProvisionStatusSkipped ProvisionStatus = "skipped"
+	ProvisionStatusFailed  ProvisionStatus = "failed"
+	// ProvisionStatusPermissionsFailed means the account was created but its permissions were not,
+	// only the permissions are created when it is resumed
+	ProvisionStatusPermissionsFailed ProvisionStatus = "permissions_failed"
+)
+
+type ProvisionResult struct {
+	Name          string          `json:"name"`
+	Status        ProvisionStatus `json:"status"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// ProvisionReport has the result of each account, failed accounts are tried again when it is resumed
+type ProvisionReport struct {
+	Results []*ProvisionResult `json:"results"`
+}
+
+func (m *ProvisionReport) Find(name string) *ProvisionResult {
+	for _, result := range m.Results {
+		if result.Name == name {
+			return result
+		}
+	}
+	return nil
+}
+
+// IsDone returns true if the account was created or skipped
+func (m *ProvisionReport) IsDone(name string) bool {
+	result := m.Find(name)
+	return result != nil && (result.Status == ProvisionStatusCreated || result.Status == ProvisionStatusSkipped)
+}
+
+func (m *ProvisionReport) Count(status ProvisionStatus) int {
+	count := 0
+	for _, result := range m.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *ProvisionReport) set(result *ProvisionResult) {
+	for i, existing := range m.Results {
+		if existing.Name == result.Name {
+			m.Results[i] = result
+			return
+		}
+	}
+	m.Results = append(m.Results, result)
+}
+
+func (m *ProvisionReport) Save(path string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed marshalling provision report, error: %v", err)
+	}
+	// a truncated report could not be loaded to resume, so it is replaced atomically
+	err = util.WriteFileAtomic(path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed writing provision report to: %v, error: %v", path, err)
+	}
+	return nil
+}
+
+// LoadProvisionReport loads the report, returns an empty report if the file does not exist
+func LoadProvisionReport(path string) (*ProvisionReport, error) {
+	report := &ProvisionReport{Results: make([]*ProvisionResult, 0)}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading provision report from: %v, error: %v", path, err)
+	}
+	err = json.Unmarshal(content, report)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling provision report from: %v, error: %v", path, err)
+	}
+	return report, nil
+}
+
+type ProvisionOpts struct {
+	// Creator pays for the accounts, their resources and the initial transfers, defaults to eosio
+	Creator interface{}
+	// CreatorPermission defaults to active
+	CreatorPermission interface{}
+	// TokenContract is used for the initial transfers, defaults to eosio.token
+	TokenContract interface{}
+	// AccountsPerTrx is the number of accounts created in each transaction, defaults to 10
+	AccountsPerTrx int
+	// ResultsFile if set is loaded to resume a previous run and saved after each transaction
+	ResultsFile string
+}
+
+type tokenTransfer struct {
+	From     eosc.AccountName `json:"from"`
+	To       eosc.AccountName `json:"to"`
+	Quantity eosc.Asset       `json:"quantity"`
+	Memo     string           `json:"memo"`
+}
+
+// ProvisionAccounts creates the accounts in batches, skipping the ones that already exist or are done
+// in the results file, if a batch fails its accounts are created one by one so that only the failing
+// ones are reported as failed, it fails before pushing anything if the signer does not hold the active
+// key of the accounts with permissions
+func (m *EOS) ProvisionAccounts(specs []*AccountSpec, opts *ProvisionOpts) (*ProvisionReport, error) {
+	return m.ProvisionAccountsCtx(context.Background(), specs, opts)
+}
+
+func (m *EOS) ProvisionAccountsCtx(ctx context.Context, specs []*AccountSpec, opts *ProvisionOpts) (*ProvisionReport, error) {
+	if err := validateAccountSpecs(specs); err != nil {
+		return nil, err
+	}
+	report := &ProvisionReport{Results: make([]*ProvisionResult, 0)}
+	if opts.ResultsFile != "" {
+		var err error
+		report, err = LoadProvisionReport(opts.ResultsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	save := func() error {
+		if opts.ResultsFile == "" {
+			return nil
+		}
+		return report.Save(opts.ResultsFile)
+	}
+	accountsPerTrx := opts.AccountsPerTrx
+	if accountsPerTrx <= 0 {
+		accountsPerTrx = defaultAccountsPerTrx
+	}
+	pending := make([]*AccountSpec, 0, len(specs))
+	pendingPermissions := make([]*AccountSpec, 0)
+	for _, spec := range specs {
+		if report.IsDone(spec.Name) {
+			continue
+		}
+		if result := report.Find(spec.Name); result != nil && result.Status == ProvisionStatusPermissionsFailed {
+			pendingPermissions = append(pendingPermissions, spec)
+			continue
+		}
+		account, err := m.FindAccountCtx(ctx, spec.Name)
+		if err != nil {
+			return report, fmt.Errorf("failed checking if account: %v exists, error: %w", spec.Name, err)
+		}
+		if account != nil {
+			report.set(&ProvisionResult{Name: spec.Name, Status: ProvisionStatusSkipped})
+			continue
+		}
+		pending = append(pending, spec)
+	}
+	if err := save(); err != nil {
+		return report, err
+	}
+	if err := m.checkPermissionsSigner(ctx, append(pendingPermissions, pending...)); err != nil {
+		return report, err
+	}
+	for _, spec := range pendingPermissions {
+		m.provisionPermissions(ctx, spec, "", report)
+		if err := save(); err != nil {
+			return report, err
+		}
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+	}
+	for start := 0; start < len(pending); start += accountsPerTrx {
+		end := start + accountsPerTrx
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+		err := m.provisionBatch(ctx, batch, opts, report)
+		if err != nil && len(batch) > 1 {
+			log.Printf("Provisioning batch of %v accounts failed, creating them one by one, error: %v", len(batch), err)
+			for _, spec := range batch {
+				m.provisionBatch(ctx, []*AccountSpec{spec}, opts, report)
+			}
+		}
+		if err := save(); err != nil {
+			return report, err
+		}
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+	}
+	return report, nil
+}
+
+// checkPermissionsSigner fails if the signer does not hold the active key of the accounts with permissions,
+// before anything is pushed, as their updateauth actions are authorized by the active permission of the account
+func (m *EOS) checkPermissionsSigner(ctx context.Context, specs []*AccountSpec) error {
+	missing := make([]string, 0)
+	var availableKeys []ecc.PublicKey
+	for _, spec := range specs {
+		if len(spec.Permissions) == 0 {
+			continue
+		}
+		if availableKeys == nil {
+			m.ensureSigner()
+			if m.API.Signer == nil {
+				return fmt.Errorf("failed provisioning accounts with permissions, no signer set")
+			}
+			var err error
+			availableKeys, err = m.API.Signer.AvailableKeys(ctx)
+			if err != nil {
+				return fmt.Errorf("failed getting signer available keys, error: %w", err)
+			}
+		}
+		activeKey := spec.ActiveKey
+		if activeKey == "" {
+			activeKey = spec.OwnerKey
+		}
+		key, err := util.ToPublicKey(activeKey)
+		if err != nil {
+			return fmt.Errorf("invalid active key of account: %v, error: %v", spec.Name, err)
+		}
+		if len(keysIntersection([]ecc.PublicKey{key}, availableKeys)) == 0 {
+			missing = append(missing, spec.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("failed provisioning accounts, the signer does not hold the active key of the accounts: %v, "+
+			"it is required to create their permissions", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// provisionBatch creates the accounts in one transaction and records the results, the permissions
+// are created afterwards, one transaction per account, as they require the account to exist
+func (m *EOS) provisionBatch(ctx context.Context, specs []*AccountSpec, opts *ProvisionOpts, report *ProvisionReport) error {
+	actions := make([]*eosc.Action, 0)
+	for _, spec := range specs {
+		specActions, err := m.GetProvisionActions(spec, opts)
+		if err != nil {
+			report.set(&ProvisionResult{Name: spec.Name, Status: ProvisionStatusFailed, Error: err.Error()})
+			return err
+		}
+		actions = append(actions, specActions...)
+	}
+	resp, err := m.TrxCtx(ctx, actions...)
+	if err != nil {
+		for _, spec := range specs {
+			report.set(&ProvisionResult{Name: spec.Name, Status: ProvisionStatusFailed, Error: err.Error()})
+		}
+		return err
+	}
+	for _, spec := range specs {
+		m.provisionPermissions(ctx, spec, resp.TransactionID, report)
+	}
+	return nil
+}
+
+// provisionPermissions creates the permissions of an account that exists and records the result,
+// transactionID is the one that created the account
+func (m *EOS) provisionPermissions(ctx context.Context, spec *AccountSpec, transactionID string, report *ProvisionReport) {
+	if result := report.Find(spec.Name); transactionID == "" && result != nil {
+		transactionID = result.TransactionID
+	}
+	result := &ProvisionResult{Name: spec.Name, Status: ProvisionStatusCreated, TransactionID: transactionID}
+	if len(spec.Permissions) > 0 {
+		actions, err := m.GetProvisionPermissionActions(spec)
+		if err == nil {
+			_, err = m.TrxCtx(ctx, actions...)
+		}
+		if err != nil {
+			result.Status = ProvisionStatusPermissionsFailed
+			result.Error = fmt.Sprintf("account created, but failed creating its permissions, error: %v", err)
+		}
+	}
+	report.set(result)
+}
+
+// GetProvisionActions returns the actions to create the account, its resources and initial transfer, the permissions
+// are created with GetProvisionPermissionActions once the account exists
+func (m *EOS) GetProvisionActions(spec *AccountSpec, opts *ProvisionOpts) ([]*eosc.Action, error) {
+	ownerKey, err := util.ToPublicKey(spec.OwnerKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner key of account: %v, error: %v", spec.Name, err)
+	}
+	createOpts := NewCreateAccountOpts(&ownerKey)
+	createOpts.Creator = opts.Creator
+	createOpts.CreatorPermission = opts.CreatorPermission
+	createOpts.RAMBytes = spec.RAMBytes
+	if spec.ActiveKey != "" {
+		activeKey, err := util.ToPublicKey(spec.ActiveKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid active key of account: %v, error: %v", spec.Name, err)
+		}
+		createOpts.Active = NewCreateAccountOpts(&activeKey).Owner
+	}
+	if spec.NetStake != "" || spec.CPUStake != "" {
+		createOpts.DelegateBW = &DelegateBWOpts{}
+		if createOpts.DelegateBW.NetStake, err = toAssetOrZero(spec.NetStake, spec.CPUStake); err != nil {
+			return nil, fmt.Errorf("invalid net stake of account: %v, error: %v", spec.Name, err)
+		}
+		if createOpts.DelegateBW.CPUStake, err = toAssetOrZero(spec.CPUStake, spec.NetStake); err != nil {
+			return nil, fmt.Errorf("invalid cpu stake of account: %v, error: %v", spec.Name, err)
+		}
+	}
+	actions, err := m.GetCreateAccountActions(spec.Name, createOpts)
+	if err != nil {
+		return nil, err
+	}
+	authorization := actions[0].Authorization
+	if spec.Transfer != "" {
+		quantity, err := util.ToAsset(spec.Transfer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer of account: %v, error: %v", spec.Name, err)
+		}
+		tokenContract := opts.TokenContract
+		if tokenContract == nil {
+			tokenContract = "eosio.token"
+		}
+		transfer, err := m.BuildAction(tokenContract, "transfer", authorization[0], &tokenTransfer{
+			From:     authorization[0].Actor,
+			To:       eosc.AN(spec.Name),
+			Quantity: quantity,
+			Memo:     spec.Memo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, transfer)
+	}
+	return actions, nil
+}
+
+// GetProvisionPermissionActions returns the updateauth actions of the permissions of the account, they are authorized
+// by its active permission, which nodeos checks before the transaction runs, so the account must already exist
+func (m *EOS) GetProvisionPermissionActions(spec *AccountSpec) ([]*eosc.Action, error) {
+	actions := make([]*eosc.Action, 0, len(spec.Permissions))
+	for _, permission := range spec.Permissions {
+		action, err := permissionSpecAction(spec.Name, permission)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// Authority builds the authority of the permission, the threshold defaults to 1
+func (m *PermissionSpec) Authority() (*eosc.Authority, error) {
+	threshold := m.Threshold
+	if threshold == 0 {
+		threshold = 1
+	}
+	builder := NewAuthorityBuilder(threshold)
+	for _, key := range m.Keys {
+		builder.Key(key, 1)
+	}
+	for _, account := range m.Accounts {
+		builder.Account(account, 1)
+	}
+	return builder.Build()
+}
+
+// ParentName returns the parent of the permission, defaults to active
+func (m *PermissionSpec) ParentName() string {
+	if m.Parent == "" {
+		return "active"
+	}
+	return m.Parent
+}
+
+func permissionSpecAction(accountName string, permission *PermissionSpec) (*eosc.Action, error) {
+	authority, err := permission.Authority()
+	if err != nil {
+		return nil, fmt.Errorf("invalid permission: %v of account: %v, error: %v", permission.Name, accountName, err)
+	}
+	return system.NewUpdateAuth(eosc.AN(accountName), eosc.PN(permission.Name), eosc.PN(permission.ParentName()), *authority, "active"), nil
+}
+
+// toAssetOrZero parses value, if it is empty returns zero with the symbol of other
+func toAssetOrZero(value, other string) (eosc.Asset, error) {
+	if value != "" {
+		return util.ToAsset(value)
+	}
+	asset, err := util.ToAsset(other)
+	if err != nil {
+		return eosc.Asset{}, err
+	}
+	asset.Amount = 0
+	return asset, nil
+}
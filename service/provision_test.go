@@ -0,0 +1,196 @@
+package service_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func TestReadAccountSpecsCSV(t *testing.T) {
+	ownerKey := newTestPublicKey(t).String()
+	activeKey := newTestPublicKey(t).String()
+	content := fmt.Sprintf(`name,owner_key,active_key,ram_bytes,net_stake,cpu_stake,transfer,memo,permissions
+accounta,%v,%v,4096,1.0000 EOS,2.0000 EOS,10.0000 EOS,welcome,claim=%v|usera@active
+accountb,%v,,,,,,,
+`, ownerKey, activeKey, activeKey, ownerKey)
+	specs, err := service.ReadAccountSpecsCSV(strings.NewReader(content))
+	assert.NilError(t, err)
+	assert.Equal(t, len(specs), 2)
+	assert.DeepEqual(t, specs[0], &service.AccountSpec{
+		Name:      "accounta",
+		OwnerKey:  ownerKey,
+		ActiveKey: activeKey,
+		RAMBytes:  4096,
+		NetStake:  "1.0000 EOS",
+		CPUStake:  "2.0000 EOS",
+		Transfer:  "10.0000 EOS",
+		Memo:      "welcome",
+		Permissions: []*service.PermissionSpec{{
+			Name:     "claim",
+			Keys:     []string{activeKey},
+			Accounts: []string{"usera@active"},
+		}},
+	})
+	assert.DeepEqual(t, specs[1], &service.AccountSpec{Name: "accountb", OwnerKey: ownerKey})
+
+	_, err = service.ReadAccountSpecsCSV(strings.NewReader("name,owner_key\naccounta,\n"))
+	assert.ErrorContains(t, err, "owner_key is required")
+	_, err = service.ReadAccountSpecsCSV(strings.NewReader(fmt.Sprintf("name,owner_key\naccounta,%v\naccounta,%v\n", ownerKey, ownerKey)))
+	assert.ErrorContains(t, err, "duplicated")
+}
+
+func TestReadAccountSpecsYAML(t *testing.T) {
+	ownerKey := newTestPublicKey(t).String()
+	content := fmt.Sprintf(`accounts:
+  - name: accounta
+    owner_key: %v
+    ram_bytes: 4096
+    transfer: 10.0000 EOS
+    permissions:
+      - name: claim
+        threshold: 2
+        accounts: [usera@active, userb@active]
+`, ownerKey)
+	specs, err := service.ReadAccountSpecsYAML(strings.NewReader(content))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, specs, []*service.AccountSpec{{
+		Name:     "accounta",
+		OwnerKey: ownerKey,
+		RAMBytes: 4096,
+		Transfer: "10.0000 EOS",
+		Permissions: []*service.PermissionSpec{{
+			Name:      "claim",
+			Threshold: 2,
+			Accounts:  []string{"usera@active", "userb@active"},
+		}},
+	}})
+	_, err = service.ReadAccountSpecsYAML(strings.NewReader("accounts:\n  - name: accounta\n    unknown: 1\n"))
+	assert.ErrorContains(t, err, "failed unmarshalling account specs")
+}
+
+func TestGetProvisionActions(t *testing.T) {
+	api, err := eosc.New("http://localhost:8888")
+	assert.NilError(t, err)
+	eos := service.NewEOS(api)
+	spec := &service.AccountSpec{
+		Name:     "accounta",
+		OwnerKey: newTestPublicKey(t).String(),
+		RAMBytes: 4096,
+		NetStake: "1.0000 EOS",
+		Transfer: "10.0000 EOS",
+		Permissions: []*service.PermissionSpec{{
+			Name:     "claim",
+			Accounts: []string{"usera@active"},
+		}},
+	}
+	actions, err := eos.GetProvisionActions(spec, &service.ProvisionOpts{Creator: "usera"})
+	assert.NilError(t, err)
+	names := make([]eosc.ActionName, 0, len(actions))
+	for _, action := range actions {
+		names = append(names, action.Name)
+	}
+	assert.DeepEqual(t, names, []eosc.ActionName{"newaccount", "buyrambytes", "delegatebw", "transfer"})
+	assert.Equal(t, actions[3].Account, eosc.AN("eosio.token"))
+	actions, err = eos.GetProvisionPermissionActions(spec)
+	assert.NilError(t, err)
+	assert.Equal(t, len(actions), 1)
+	assert.Equal(t, actions[0].Name, eosc.ActN("updateauth"))
+	assert.DeepEqual(t, actions[0].Authorization, []eosc.PermissionLevel{{Actor: "accounta", Permission: "active"}})
+
+	spec.OwnerKey = "invalid"
+	_, err = eos.GetProvisionActions(spec, &service.ProvisionOpts{})
+	assert.ErrorContains(t, err, "invalid owner key of account: accounta")
+}
+
+func TestProvisionReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	report, err := service.LoadProvisionReport(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Results), 0)
+	report.Results = append(report.Results,
+		&service.ProvisionResult{Name: "accounta", Status: service.ProvisionStatusCreated, TransactionID: "abc"},
+		&service.ProvisionResult{Name: "accountb", Status: service.ProvisionStatusFailed, Error: "failed"},
+		&service.ProvisionResult{Name: "accountd", Status: service.ProvisionStatusPermissionsFailed, Error: "failed"},
+	)
+	assert.NilError(t, report.Save(path))
+	loaded, err := service.LoadProvisionReport(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, loaded, report)
+	assert.Assert(t, loaded.IsDone("accounta"))
+	assert.Assert(t, !loaded.IsDone("accountb"))
+	assert.Assert(t, !loaded.IsDone("accountc"))
+	assert.Assert(t, !loaded.IsDone("accountd"))
+	assert.Equal(t, loaded.Count(service.ProvisionStatusFailed), 1)
+}
+
+func TestProvisionAccounts(t *testing.T) {
+	E.Setup(t)
+	eos := service.NewEOS(E.A)
+	key := service.GetEOSIOPublicKey()
+	_, err := eos.CreateAccount("provexist", key, false)
+	assert.NilError(t, err)
+	newSpec := func(name string, permissions ...*service.PermissionSpec) *service.AccountSpec {
+		return &service.AccountSpec{Name: name, OwnerKey: key.String(), Permissions: permissions}
+	}
+	specs := []*service.AccountSpec{
+		newSpec("provexist"),
+		newSpec("provnewa"),
+		// names longer than 12 characters are rejected by newaccount, which fails the batch
+		newSpec("provbadnamexa"),
+		newSpec("provnewb"),
+		// provlater does not exist yet so the permission can not be created
+		newSpec("provperm", &service.PermissionSpec{Name: "claim", Accounts: []string{"provlater@active"}}),
+	}
+	path := filepath.Join(t.TempDir(), "results.json")
+	opts := &service.ProvisionOpts{ResultsFile: path}
+	report, err := eos.ProvisionAccounts(specs, opts)
+	assert.NilError(t, err)
+	statuses := func(report *service.ProvisionReport) map[string]service.ProvisionStatus {
+		statuses := make(map[string]service.ProvisionStatus, len(report.Results))
+		for _, result := range report.Results {
+			statuses[result.Name] = result.Status
+		}
+		return statuses
+	}
+	assert.DeepEqual(t, statuses(report), map[string]service.ProvisionStatus{
+		"provexist":     service.ProvisionStatusSkipped,
+		"provnewa":      service.ProvisionStatusCreated,
+		"provbadnamexa": service.ProvisionStatusFailed,
+		"provnewb":      service.ProvisionStatusCreated,
+		"provperm":      service.ProvisionStatusPermissionsFailed,
+	})
+	createdTrxID := report.Find("provnewa").TransactionID
+	assert.Assert(t, createdTrxID != "")
+	for _, name := range []string{"provnewa", "provnewb", "provperm"} {
+		_, err = eos.GetAccount(name)
+		assert.NilError(t, err)
+	}
+
+	_, err = eos.CreateAccount("provlater", key, false)
+	assert.NilError(t, err)
+	report, err = eos.ProvisionAccounts(specs, opts)
+	assert.NilError(t, err)
+	assert.Equal(t, report.Find("provperm").Status, service.ProvisionStatusCreated)
+	assert.Equal(t, report.Find("provbadnamexa").Status, service.ProvisionStatusFailed)
+	assert.Equal(t, report.Find("provnewa").TransactionID, createdTrxID)
+	_, err = eos.GetAccountPermission("provperm", "claim")
+	assert.NilError(t, err)
+	loaded, err := service.LoadProvisionReport(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, loaded, report)
+
+	_, err = eos.ProvisionAccounts([]*service.AccountSpec{{
+		Name:        "provnokey",
+		OwnerKey:    newTestPublicKey(t).String(),
+		Permissions: []*service.PermissionSpec{{Name: "claim", Accounts: []string{"usera@active"}}},
+	}}, &service.ProvisionOpts{})
+	assert.ErrorContains(t, err, "does not hold the active key of the accounts: provnokey")
+	account, err := eos.FindAccount("provnokey")
+	assert.NilError(t, err)
+	assert.Assert(t, account == nil)
+}
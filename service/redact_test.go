@@ -0,0 +1,16 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func TestGetKeyDoesNotLeakKey(t *testing.T) {
+	invalidKey := service.EOSIOKey[:len(service.EOSIOKey)-1] + "x"
+	_, err := service.GetKey(invalidKey)
+	assert.Assert(t, err != nil)
+	assert.Assert(t, !strings.Contains(err.Error(), invalidKey))
+}
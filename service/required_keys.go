@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go/ecc"
+)
+
+// maxAuthorityDepth mirrors the max_authority_depth of the eosio chain config
+const maxAuthorityDepth = 6
+
+const eosioCodePermission = "eosio.code"
+
+// RequiredKeysMode determines how the keys that have to sign a transaction are resolved
+type RequiredKeysMode int
+
+const (
+	// RequiredKeysChain calls get_required_keys with the keys available to the signer
+	RequiredKeysChain RequiredKeysMode = iota
+	// RequiredKeysLocal resolves the keys from the permissions of the accounts returned by get_account,
+	// so the keys available to the signer are not disclosed to the node
+	RequiredKeysLocal
+)
+
+// RequiredKeys returns the minimal set of keys available to the signer that satisfy the
+// authorizations of the transaction, if some authorization can not be satisfied an
+// eoserr.UnsatisfiedAuthError listing the permissions is returned
+func (m *EOS) RequiredKeys(tx *eosc.Transaction) ([]ecc.PublicKey, error) {
+	return m.RequiredKeysCtx(context.Background(), tx)
+}
+
+func (m *EOS) RequiredKeysCtx(ctx context.Context, tx *eosc.Transaction) ([]ecc.PublicKey, error) {
+	m.ensureSigner()
+	if m.API.Signer == nil {
+		return nil, fmt.Errorf("failed resolving required keys, no signer set")
+	}
+	availableKeys, err := m.API.Signer.AvailableKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting signer available keys, error: %w", err)
+	}
+	if m.RequiredKeysMode == RequiredKeysLocal {
+		return m.resolveKeysLocally(ctx, tx, availableKeys)
+	}
+	return m.chainRequiredKeys(ctx, tx, availableKeys)
+}
+
+// chainRequiredKeys calls get_required_keys, if the keys can not satisfy the transaction the
+// permissions that are not satisfied are resolved locally to be reported in the error
+func (m *EOS) chainRequiredKeys(ctx context.Context, tx *eosc.Transaction, availableKeys []ecc.PublicKey) ([]ecc.PublicKey, error) {
+	var resp eosc.GetRequiredKeysResp
+	err := m.withRetries(ctx, func() error {
+		return m.API.Call(ctx, "chain", "get_required_keys", M{"transaction": tx, "available_keys": availableKeys}, &resp)
+	})
+	if eoserr.IsUnsatisfiedAuth(err) {
+		// the node does not report which permission failed, resolve locally to find out
+		if _, localErr := m.resolveKeysLocally(ctx, tx, availableKeys); localErr != nil {
+			return nil, localErr
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed getting required keys, error: %w", err)
+	}
+	return resp.RequiredKeys, nil
+}
+
+// SignTrx signs the transaction only with the required keys and packs it
+func (m *EOS) SignTrx(tx *eosc.Transaction, chainID eosc.Checksum256) (*eosc.SignedTransaction, *eosc.PackedTransaction, error) {
+	return m.SignTrxCtx(context.Background(), tx, chainID)
+}
+
+func (m *EOS) SignTrxCtx(ctx context.Context, tx *eosc.Transaction, chainID eosc.Checksum256) (*eosc.SignedTransaction, *eosc.PackedTransaction, error) {
+	requiredKeys, err := m.RequiredKeysCtx(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	signedTx, err := m.API.Signer.Sign(ctx, eosc.NewSignedTransaction(tx), chainID, requiredKeys...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed signing transaction, error: %w", err)
+	}
+	packedTx, err := signedTx.Pack(eosc.CompressionNone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed packing transaction, error: %v", err)
+	}
+	return signedTx, packedTx, nil
+}
+
+func (m *EOS) signPushTrx(ctx context.Context, actions []*eosc.Action, txOpts *eosc.TxOptions) (*eosc.PushTransactionFullResp, error) {
+	tx := eosc.NewTransaction(actions, txOpts)
+	_, packedTx, err := m.SignTrxCtx(ctx, tx, txOpts.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return m.API.PushTransaction(ctx, packedTx)
+}
+
+func (m *EOS) resolveKeysLocally(ctx context.Context, tx *eosc.Transaction, availableKeys []ecc.PublicKey) ([]ecc.PublicKey, error) {
+	resolver := newAuthorityResolver(m, availableKeys)
+	requiredKeys := make([]ecc.PublicKey, 0)
+	unsatisfied := make([]string, 0)
+	seen := make(map[eosc.PermissionLevel]bool)
+	for _, action := range trxActions(tx) {
+		for _, auth := range action.Authorization {
+			if seen[auth] {
+				continue
+			}
+			seen[auth] = true
+			keys, ok, err := resolver.satisfy(ctx, auth, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed resolving keys for permission: %v@%v, error: %w", auth.Actor, auth.Permission, err)
+			}
+			if !ok {
+				unsatisfied = append(unsatisfied, fmt.Sprintf("%v@%v", auth.Actor, auth.Permission))
+				continue
+			}
+			requiredKeys = append(requiredKeys, keysDifference(keys, requiredKeys)...)
+		}
+	}
+	if len(unsatisfied) > 0 {
+		return nil, &eoserr.UnsatisfiedAuthError{Permissions: unsatisfied}
+	}
+	return requiredKeys, nil
+}
+
+// authorityResolver finds the available keys that satisfy a permission, following the
+// account permissions of its authority
+type authorityResolver struct {
+	eos       *EOS
+	available map[string]bool
+	accounts  map[eosc.AccountName]*eosc.AccountResp
+}
+
+func newAuthorityResolver(eos *EOS, availableKeys []ecc.PublicKey) *authorityResolver {
+	resolver := &authorityResolver{
+		eos:       eos,
+		available: make(map[string]bool, len(availableKeys)),
+		accounts:  make(map[eosc.AccountName]*eosc.AccountResp),
+	}
+	for _, key := range availableKeys {
+		resolver.available[key.String()] = true
+	}
+	return resolver
+}
+
+// trxActions returns the context free actions followed by the actions of the transaction
+func trxActions(tx *eosc.Transaction) []*eosc.Action {
+	actions := make([]*eosc.Action, 0, len(tx.ContextFreeActions)+len(tx.Actions))
+	return append(append(actions, tx.ContextFreeActions...), tx.Actions...)
+}
+
+// authorityOption is a way of adding weight to an authority, either a key or an account permission
+type authorityOption struct {
+	weight uint16
+	keys   []ecc.PublicKey
+}
+
+func (m *authorityResolver) satisfy(ctx context.Context, level eosc.PermissionLevel, depth int) ([]ecc.PublicKey, bool, error) {
+	if depth > maxAuthorityDepth || level.Permission == eosioCodePermission {
+		return nil, false, nil
+	}
+	authority, err := m.authority(ctx, level)
+	if err != nil {
+		return nil, false, err
+	}
+	if authority == nil {
+		return nil, false, nil
+	}
+	options := make([]*authorityOption, 0, len(authority.Keys)+len(authority.Accounts))
+	for _, key := range authority.Keys {
+		if m.available[key.PublicKey.String()] {
+			options = append(options, &authorityOption{
+				weight: key.Weight,
+				keys:   []ecc.PublicKey{key.PublicKey},
+			})
+		}
+	}
+	for _, account := range authority.Accounts {
+		keys, ok, err := m.satisfy(ctx, account.Permission, depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			options = append(options, &authorityOption{
+				weight: account.Weight,
+				keys:   keys,
+			})
+		}
+	}
+	// heaviest options first, and the ones that require less keys on a tie, to minimize the signatures
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].weight != options[j].weight {
+			return options[i].weight > options[j].weight
+		}
+		return len(options[i].keys) < len(options[j].keys)
+	})
+	keys := make([]ecc.PublicKey, 0)
+	var weight uint32
+	for _, option := range options {
+		if weight >= authority.Threshold {
+			break
+		}
+		weight += uint32(option.weight)
+		keys = append(keys, keysDifference(option.keys, keys)...)
+	}
+	if weight < authority.Threshold {
+		return nil, false, nil
+	}
+	return keys, true, nil
+}
+
+// candidateKeys returns every key that can take part in satisfying the permission, following the
+// account permissions of its authority, regardless of the keys available to the signer
+func (m *authorityResolver) candidateKeys(ctx context.Context, level eosc.PermissionLevel, depth int) ([]ecc.PublicKey, error) {
+	if depth > maxAuthorityDepth || level.Permission == eosioCodePermission {
+		return nil, nil
+	}
+	authority, err := m.authority(ctx, level)
+	if err != nil || authority == nil {
+		return nil, err
+	}
+	keys := make([]ecc.PublicKey, 0, len(authority.Keys))
+	for _, key := range authority.Keys {
+		keys = append(keys, key.PublicKey)
+	}
+	for _, account := range authority.Accounts {
+		accountKeys, err := m.candidateKeys(ctx, account.Permission, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, keysDifference(accountKeys, keys)...)
+	}
+	return keys, nil
+}
+
+// authority returns nil if the account or permission does not exist
+func (m *authorityResolver) authority(ctx context.Context, level eosc.PermissionLevel) (*eosc.Authority, error) {
+	account, ok := m.accounts[level.Actor]
+	if !ok {
+		var err error
+		account, err = m.eos.GetAccountCtx(ctx, level.Actor)
+		if err != nil && !errors.Is(err, eoserr.ErrAccountNotFound) {
+			return nil, err
+		}
+		m.accounts[level.Actor] = account
+	}
+	if account == nil {
+		return nil, nil
+	}
+	for _, permission := range account.Permissions {
+		if permission.PermName == string(level.Permission) {
+			return &permission.RequiredAuth, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"gotest.tools/assert"
+)
+
+func TestRequiredKeysModeFromOptions(t *testing.T) {
+	var requiredKeysCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chain/get_account":
+			fmt.Fprintf(w, `{"account_name": "eosio", "permissions": [{"perm_name": "active", "parent": "owner", "required_auth": {"threshold": 1, "keys": [{"key": %q, "weight": 1}], "accounts": [], "waits": []}}]}`, eosioPublicKey)
+		case "/v1/chain/get_required_keys":
+			atomic.AddInt32(&requiredKeysCalls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	keyBag := eosc.NewKeyBag()
+	assert.NilError(t, keyBag.Add(service.EOSIOKey))
+	api, err := eosc.New(server.URL)
+	assert.NilError(t, err)
+	eos := service.NewEOSWithOptions(api, &service.EOSOpts{
+		Signer:           keyBag,
+		RequiredKeysMode: service.RequiredKeysLocal,
+	})
+	assert.Equal(t, eos.RequiredKeysMode, service.RequiredKeysLocal)
+
+	requiredKeys, err := eos.RequiredKeysCtx(context.Background(), newTestSignedTrx().Transaction)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, requiredKeys, []ecc.PublicKey{ecc.MustNewPublicKey(eosioPublicKey)})
+	assert.Equal(t, atomic.LoadInt32(&requiredKeysCalls), int32(0))
+}
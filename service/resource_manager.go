@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/system"
+)
+
+const defaultMaxTopUpsPerTrx = 3
+
+// ramFee is the fee charged by eosio.system when buying RAM
+const ramFee = 0.005
+
+type Resource string
+
+const (
+	ResourceRAM Resource = "ram"
+	ResourceCPU Resource = "cpu"
+	ResourceNET Resource = "net"
+)
+
+// ResourcePolicy specifies how the resource manager tops up accounts that run out of resources
+type ResourcePolicy struct {
+	// Funder pays for the resources, its keys must be available to the signer
+	Funder           eosc.AccountName
+	FunderPermission eosc.PermissionName
+	// RAMBytes is the minimum number of bytes bought on each RAM top up, at least the missing bytes are bought
+	RAMBytes uint32
+	// CPUStake and NETStake are delegated on each CPU or NET top up when not using powerup
+	CPUStake eosc.Asset
+	NETStake eosc.Asset
+	// TransferStake transfers the stake to the receiver instead of delegating it
+	TransferStake bool
+	// UsePowerup rents CPU and NET using the powerup action instead of delegating stake, the powerup
+	// is not done if its fee is greater than PowerupMaxPayment
+	UsePowerup bool
+	// PowerupDays defaults to the days configured in the powerup state
+	PowerupDays       uint32
+	PowerupCPUFrac    int64
+	PowerupNETFrac    int64
+	PowerupMaxPayment eosc.Asset
+	// MaxSpend is the total the manager can spend, top ups that would exceed it are not done, it is
+	// required and the costs of the top ups must be in its symbol
+	MaxSpend eosc.Asset
+	// MaxTopUpsPerTrx limits the number of top ups done to get a single transaction through
+	MaxTopUpsPerTrx int
+}
+
+// ResourceTopUp records a top up done by the resource manager
+type ResourceTopUp struct {
+	Account  eosc.AccountName `json:"account"`
+	Resource Resource         `json:"resource"`
+	Action   eosc.ActionName  `json:"action"`
+	// Cost is the amount spent, for RAM and powerup it is the fee estimated before buying
+	Cost          eosc.Asset `json:"cost"`
+	TransactionID string     `json:"transaction_id"`
+	Time          time.Time  `json:"time"`
+}
+
+func (m *ResourceTopUp) String() string {
+	return fmt.Sprintf("Account: %v, Resource: %v, Action: %v, Cost: %v, TransactionID: %v", m.Account, m.Resource, m.Action, m.Cost, m.TransactionID)
+}
+
+// ResourceManager buys RAM, delegates bandwidth or uses powerup from a funding account when
+// a transaction fails because an account ran out of resources, so that it can be retried
+type ResourceManager struct {
+	eos    *EOS
+	policy *ResourcePolicy
+	mutex  sync.Mutex
+	spent  eosc.Asset
+	// reserved is the cost of the top ups being pushed, it counts against MaxSpend until they are done
+	reserved eosc.Int64
+	topUps   []*ResourceTopUp
+}
+
+func NewResourceManager(eos *EOS, policy *ResourcePolicy) *ResourceManager {
+	return &ResourceManager{
+		eos:    eos,
+		policy: policy,
+		spent:  eosc.Asset{Symbol: policy.MaxSpend.Symbol},
+	}
+}
+
+// Spent returns the total spent by the manager
+func (m *ResourceManager) Spent() eosc.Asset {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.spent
+}
+
+// TopUps returns the top ups done by the manager
+func (m *ResourceManager) TopUps() []*ResourceTopUp {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	topUps := make([]*ResourceTopUp, len(m.topUps))
+	copy(topUps, m.topUps)
+	return topUps
+}
+
+// TopUp tops up the resource that caused the transaction with the specified actions to fail,
+// returns nil if the error is not caused by resource exhaustion
+func (m *ResourceManager) TopUp(ctx context.Context, actions []*eosc.Action, trxErr error) (*ResourceTopUp, error) {
+	var topUp *ResourceTopUp
+	var action *eosc.Action
+	var err error
+	// contracts can bill RAM to other accounts, so the account named by the error is topped up
+	account, ok := eoserr.ResourceExhaustedAccount(trxErr)
+	if !ok {
+		account = payer(actions)
+	}
+	if eoserr.IsRAMExhausted(trxErr) {
+		_, missingBytes, _ := eoserr.InsufficientRAM(trxErr)
+		topUp, action, err = m.buildRAMTopUp(ctx, account, missingBytes)
+	} else if eoserr.IsCPUExhausted(trxErr) {
+		topUp, action, err = m.buildBandwidthTopUp(ctx, ResourceCPU, account)
+	} else if eoserr.IsNETExhausted(trxErr) {
+		topUp, action, err = m.buildBandwidthTopUp(ctx, ResourceNET, account)
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if topUp.Account == "" {
+		return nil, fmt.Errorf("failed topping up %v, could not determine the account to top up", topUp.Resource)
+	}
+	err = m.reserve(topUp)
+	if err != nil {
+		return nil, err
+	}
+	// the top up transaction must not trigger another top up
+	resp, err := m.eos.TrxCtx(withoutResourceManager(ctx), action)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.reserved -= topUp.Cost.Amount
+	if err != nil {
+		return nil, fmt.Errorf("failed topping up %v of account: %v, error: %w", topUp.Resource, topUp.Account, err)
+	}
+	topUp.TransactionID = resp.TransactionID
+	topUp.Time = time.Now()
+	m.spent.Amount += topUp.Cost.Amount
+	m.topUps = append(m.topUps, topUp)
+	log.Print(util.Redact(fmt.Sprintf("Resource manager topped up %v of account: %v, using: %v, spent: %v, total spent: %v", topUp.Resource, topUp.Account, topUp.Action, topUp.Cost, m.spent)))
+	return topUp, nil
+}
+
+// reserve checks that the top up does not exceed the max spend and reserves its cost, so that the
+// lock is not held while the top up is pushed
+func (m *ResourceManager) reserve(topUp *ResourceTopUp) error {
+	maxSpend := m.policy.MaxSpend
+	if maxSpend.Amount <= 0 {
+		return fmt.Errorf("failed topping up %v of account: %v, the resource policy max spend must be positive", topUp.Resource, topUp.Account)
+	}
+	if topUp.Cost.Symbol != maxSpend.Symbol {
+		return fmt.Errorf("failed topping up %v of account: %v, cost: %v is not in the symbol of max spend: %v",
+			topUp.Resource, topUp.Account, topUp.Cost, maxSpend)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.spent.Amount+m.reserved+topUp.Cost.Amount > maxSpend.Amount {
+		return fmt.Errorf("failed topping up %v of account: %v, cost: %v would exceed max spend: %v, already spent: %v",
+			topUp.Resource, topUp.Account, topUp.Cost, maxSpend, m.spent)
+	}
+	m.reserved += topUp.Cost.Amount
+	return nil
+}
+
+func (m *ResourceManager) buildRAMTopUp(ctx context.Context, account eosc.AccountName, missingBytes uint64) (*ResourceTopUp, *eosc.Action, error) {
+	bytes := uint64(m.policy.RAMBytes)
+	if missingBytes > bytes {
+		bytes = missingBytes
+	}
+	if bytes == 0 {
+		return nil, nil, fmt.Errorf("failed topping up ram of account: %v, the error does not state the missing bytes and the resource policy ram bytes is not set", account)
+	}
+	cost, err := m.eos.EstimateRAMCostCtx(ctx, uint32(bytes))
+	if err != nil {
+		return nil, nil, err
+	}
+	action := system.NewBuyRAMBytes(m.policy.Funder, account, uint32(bytes))
+	action.Authorization = []eosc.PermissionLevel{m.funderPermission()}
+	return &ResourceTopUp{
+		Account:  account,
+		Resource: ResourceRAM,
+		Action:   action.Name,
+		Cost:     cost,
+	}, action, nil
+}
+
+func (m *ResourceManager) buildBandwidthTopUp(ctx context.Context, resource Resource, account eosc.AccountName) (*ResourceTopUp, *eosc.Action, error) {
+	topUp := &ResourceTopUp{
+		Account:  account,
+		Resource: resource,
+	}
+	var action *eosc.Action
+	if m.policy.UsePowerup {
+		var netFrac, cpuFrac int64
+		if resource == ResourceCPU {
+			cpuFrac = m.policy.PowerupCPUFrac
+		} else {
+			netFrac = m.policy.PowerupNETFrac
+		}
+		state, err := m.eos.GetPowerupStateCtx(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		fee, err := state.Fee(netFrac, cpuFrac, time.Now())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed calculating powerup fee, error: %v", err)
+		}
+		if fee.Amount > m.policy.PowerupMaxPayment.Amount {
+			return nil, nil, fmt.Errorf("powerup fee: %v is greater than max payment: %v", fee, m.policy.PowerupMaxPayment)
+		}
+		days := m.policy.PowerupDays
+		if days == 0 {
+			days = state.PowerupDays
+		}
+		action, err = m.eos.BuildPowerupAction(m.policy.Funder, account, days, netFrac, cpuFrac, m.policy.PowerupMaxPayment)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed building powerup action, error: %v", err)
+		}
+		action.Authorization = []eosc.PermissionLevel{m.funderPermission()}
+		topUp.Cost = fee
+	} else {
+		stakeCPU := eosc.Asset{Symbol: m.policy.CPUStake.Symbol}
+		stakeNET := eosc.Asset{Symbol: m.policy.NETStake.Symbol}
+		if resource == ResourceCPU {
+			stakeCPU = m.policy.CPUStake
+			stakeNET.Symbol = stakeCPU.Symbol
+			topUp.Cost = stakeCPU
+		} else {
+			stakeNET = m.policy.NETStake
+			stakeCPU.Symbol = stakeNET.Symbol
+			topUp.Cost = stakeNET
+		}
+		action = system.NewDelegateBW(m.policy.Funder, account, stakeCPU, stakeNET, m.policy.TransferStake)
+		action.Authorization = []eosc.PermissionLevel{m.funderPermission()}
+	}
+	topUp.Action = action.Name
+	return topUp, action, nil
+}
+
+func (m *ResourceManager) funderPermission() eosc.PermissionLevel {
+	permission := m.policy.FunderPermission
+	if permission == "" {
+		permission = "active"
+	}
+	return eosc.PermissionLevel{
+		Actor:      m.policy.Funder,
+		Permission: permission,
+	}
+}
+
+func (m *ResourceManager) maxTopUpsPerTrx() int {
+	if m.policy.MaxTopUpsPerTrx > 0 {
+		return m.policy.MaxTopUpsPerTrx
+	}
+	return defaultMaxTopUpsPerTrx
+}
+
+// payer returns the first authorizer of the transaction, it is the account topped up when the resource
+// exhaustion error does not name one
+func payer(actions []*eosc.Action) eosc.AccountName {
+	for _, action := range actions {
+		if len(action.Authorization) > 0 {
+			return action.Authorization[0].Actor
+		}
+	}
+	return ""
+}
+
+type resourceManagerDisabledKey struct{}
+
+func withoutResourceManager(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resourceManagerDisabledKey{}, true)
+}
+
+func isResourceManagerDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(resourceManagerDisabledKey{}).(bool)
+	return disabled
+}
+
+// pushWithTopUps pushes the actions, topping up the resources of the accounts involved if the
+// transaction fails due to resource exhaustion and a resource manager is configured, once top ups
+// are attempted the error returned is not retried, so that MaxTopUpsPerTrx holds for the transaction
+func (m *EOS) pushWithTopUps(ctx context.Context, actions []*eosc.Action) (*eosc.PushTransactionFullResp, error) {
+	resp, err := m.signPushActions(ctx, actions)
+	if err == nil || m.ResourceManager == nil || isResourceManagerDisabled(ctx) {
+		return resp, err
+	}
+	maxTopUps := m.ResourceManager.maxTopUpsPerTrx()
+	for i := 0; i < maxTopUps; i++ {
+		topUp, topUpErr := m.ResourceManager.TopUp(ctx, actions, err)
+		if topUpErr != nil {
+			return nil, &permanentError{err: fmt.Errorf("%w, resource top up failed: %v", err, topUpErr)}
+		}
+		if topUp == nil {
+			return nil, err
+		}
+		resp, err = m.signPushActions(ctx, actions)
+		if err == nil {
+			return resp, nil
+		}
+	}
+	if eoserr.IsResourceExhausted(err) {
+		return nil, &permanentError{err: fmt.Errorf("%w, max top ups per transaction: %v reached", err, maxTopUps)}
+	}
+	return nil, err
+}
+
+type ramMarket struct {
+	Base struct {
+		Balance eosc.Asset `json:"balance"`
+	} `json:"base"`
+	Quote struct {
+		Balance eosc.Asset `json:"balance"`
+	} `json:"quote"`
+}
+
+// EstimateRAMCost returns the cost of buying the specified number of bytes of RAM according to the rammarket table
+func (m *EOS) EstimateRAMCost(bytes uint32) (eosc.Asset, error) {
+	return m.EstimateRAMCostCtx(context.Background(), bytes)
+}
+
+func (m *EOS) EstimateRAMCostCtx(ctx context.Context, bytes uint32) (eosc.Asset, error) {
+	var markets []*ramMarket
+	err := m.GetTableRowsCtx(ctx, eosc.GetTableRowsRequest{
+		Code:  "eosio",
+		Scope: "eosio",
+		Table: "rammarket",
+		Limit: 1,
+	}, &markets)
+	if err != nil {
+		return eosc.Asset{}, fmt.Errorf("failed getting ram market, error: %v", err)
+	}
+	if len(markets) == 0 {
+		return eosc.Asset{}, fmt.Errorf("failed getting ram market, rammarket table is empty")
+	}
+	market := markets[0]
+	base := float64(market.Base.Balance.Amount)
+	quote := float64(market.Quote.Balance.Amount)
+	if base <= float64(bytes) {
+		return eosc.Asset{}, fmt.Errorf("failed estimating ram cost, not enough ram in market to buy: %v bytes", bytes)
+	}
+	// bancor with equal weights, the amount of quote required to get bytes out of the market
+	cost := quote * float64(bytes) / (base - float64(bytes))
+	return eosc.Asset{
+		Amount: eosc.Int64(math.Ceil(cost / (1 - ramFee))),
+		Symbol: market.Quote.Balance.Symbol,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func eosAsset(amount int64) eosc.Asset {
+	return eosc.Asset{
+		Amount: eosc.Int64(amount),
+		Symbol: eosc.Symbol{Precision: 4, Symbol: "EOS"},
+	}
+}
+
+func TestResourceManagerIgnoresOtherErrors(t *testing.T) {
+	manager := service.NewResourceManager(service.NewEOS(E.A), &service.ResourcePolicy{
+		Funder:   "funder",
+		CPUStake: eosAsset(10000),
+		MaxSpend: eosAsset(100000),
+	})
+	topUp, err := manager.TopUp(context.Background(), newAccountActions("usera"), errors.New("assertion failure with message: overdrawn balance"))
+	assert.NilError(t, err)
+	assert.Assert(t, topUp == nil)
+}
+
+func TestResourceManagerSpendingCap(t *testing.T) {
+	manager := service.NewResourceManager(service.NewEOS(E.A), &service.ResourcePolicy{
+		Funder:   "funder",
+		CPUStake: eosAsset(20000),
+		MaxSpend: eosAsset(10000),
+	})
+	cpuErr := errors.New("billed CPU time (500 us) is greater than the maximum billable CPU time for the transaction; exceeded the current CPU usage limit imposed on the transaction")
+	topUp, err := manager.TopUp(context.Background(), newAccountActions("usera"), cpuErr)
+	assert.ErrorContains(t, err, "would exceed max spend")
+	assert.Assert(t, topUp == nil)
+	assert.Equal(t, manager.Spent().Amount, eosc.Int64(0))
+	assert.Equal(t, len(manager.TopUps()), 0)
+}
+
+func TestResourceManagerRequiresMaxSpend(t *testing.T) {
+	cpuErr := errors.New("billed CPU time (500 us) is greater than the maximum billable CPU time for the transaction; exceeded the current CPU usage limit imposed on the transaction")
+	manager := service.NewResourceManager(service.NewEOS(E.A), &service.ResourcePolicy{
+		Funder:   "funder",
+		CPUStake: eosAsset(10000),
+	})
+	_, err := manager.TopUp(context.Background(), newAccountActions("usera"), cpuErr)
+	assert.ErrorContains(t, err, "max spend must be positive")
+
+	manager = service.NewResourceManager(service.NewEOS(E.A), &service.ResourcePolicy{
+		Funder:   "funder",
+		CPUStake: eosAsset(10000),
+		MaxSpend: eosc.Asset{Amount: 100000, Symbol: eosc.Symbol{Precision: 4, Symbol: "TLOS"}},
+	})
+	_, err = manager.TopUp(context.Background(), newAccountActions("usera"), cpuErr)
+	assert.ErrorContains(t, err, "is not in the symbol of max spend")
+	assert.Equal(t, len(manager.TopUps()), 0)
+}
+
+func TestResourceManagerTopsUpAccountNamedByError(t *testing.T) {
+	manager := service.NewResourceManager(service.NewEOS(E.A), &service.ResourcePolicy{
+		Funder:   "funder",
+		CPUStake: eosAsset(20000),
+		MaxSpend: eosAsset(10000),
+	})
+	cpuErr := errors.New("authorizing account 'userb' has insufficient cpu resources for this transaction")
+	_, err := manager.TopUp(context.Background(), newAccountActions("usera"), cpuErr)
+	assert.ErrorContains(t, err, "failed topping up cpu of account: userb")
+
+	cpuErr = errors.New("billed CPU time (500 us) is greater than the maximum billable CPU time for the transaction; exceeded the current CPU usage limit imposed on the transaction")
+	_, err = manager.TopUp(context.Background(), newAccountActions("usera"), cpuErr)
+	// the newaccount actions are authorized by eosio
+	assert.ErrorContains(t, err, "failed topping up cpu of account: eosio")
+}
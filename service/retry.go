@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+)
+
+// RetryPolicy decides if a failed call should be retried and how long to wait before doing so,
+// attempt is the number of failed attempts so far starting at 1, and elapsed the time since the
+// first attempt was made
+type RetryPolicy interface {
+	NextDelay(attempt uint, elapsed time.Duration) (time.Duration, bool)
+}
+
+// ConstantRetryPolicy retries up to Retries times waiting Delay between attempts
+type ConstantRetryPolicy struct {
+	Retries uint
+	Delay   time.Duration
+}
+
+func NewConstantRetryPolicy(retries uint, delay time.Duration) *ConstantRetryPolicy {
+	return &ConstantRetryPolicy{
+		Retries: retries,
+		Delay:   delay,
+	}
+}
+
+func (m *ConstantRetryPolicy) NextDelay(attempt uint, elapsed time.Duration) (time.Duration, bool) {
+	if attempt > m.Retries {
+		return 0, false
+	}
+	return m.Delay, true
+}
+
+// ExponentialRetryPolicy retries up to Retries times, the delay starts at InitialDelay and is
+// multiplied by Multiplier on each attempt up to MaxDelay, Jitter is the fraction (0-1) by which
+// the delay is randomly increased or decreased to avoid clients retrying in lockstep
+type ExponentialRetryPolicy struct {
+	Retries      uint
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	Jitter       float64
+}
+
+func NewExponentialRetryPolicy(retries uint, initialDelay, maxDelay time.Duration) *ExponentialRetryPolicy {
+	return &ExponentialRetryPolicy{
+		Retries:      retries,
+		InitialDelay: initialDelay,
+		MaxDelay:     maxDelay,
+		Multiplier:   2,
+		Jitter:       0.2,
+	}
+}
+
+func (m *ExponentialRetryPolicy) NextDelay(attempt uint, elapsed time.Duration) (time.Duration, bool) {
+	if attempt > m.Retries {
+		return 0, false
+	}
+	multiplier := m.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(m.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if m.MaxDelay > 0 && delay > float64(m.MaxDelay) {
+		delay = float64(m.MaxDelay)
+	}
+	if m.Jitter > 0 {
+		delay = delay * (1 + m.Jitter*(2*rand.Float64()-1))
+	}
+	return time.Duration(delay), true
+}
+
+// MaxElapsedTimeRetryPolicy stops retrying once waiting for the next attempt would exceed
+// MaxElapsedTime since the first attempt, delays are taken from Policy
+type MaxElapsedTimeRetryPolicy struct {
+	Policy         RetryPolicy
+	MaxElapsedTime time.Duration
+}
+
+func NewMaxElapsedTimeRetryPolicy(policy RetryPolicy, maxElapsedTime time.Duration) *MaxElapsedTimeRetryPolicy {
+	return &MaxElapsedTimeRetryPolicy{
+		Policy:         policy,
+		MaxElapsedTime: maxElapsedTime,
+	}
+}
+
+func (m *MaxElapsedTimeRetryPolicy) NextDelay(attempt uint, elapsed time.Duration) (time.Duration, bool) {
+	delay, ok := m.Policy.NextDelay(attempt, elapsed)
+	if !ok || elapsed+delay > m.MaxElapsedTime {
+		return 0, false
+	}
+	return delay, true
+}
+
+type maxRetriesPolicy struct {
+	policy  RetryPolicy
+	retries uint
+}
+
+func (m *maxRetriesPolicy) NextDelay(attempt uint, elapsed time.Duration) (time.Duration, bool) {
+	if attempt > m.retries {
+		return 0, false
+	}
+	return m.policy.NextDelay(attempt, elapsed)
+}
+
+// ErrorClassifier returns true if the error is transient and the call that caused it can be retried
+type ErrorClassifier func(err error) bool
+
+var retryableNetworkErrorMsgs = []string{
+	"deadline",
+	"connection reset by peer",
+}
+
+// DefaultErrorClassifier classifies as retryable the transient errors reported by nodeos, using
+// their error codes, and network timeouts
+func DefaultErrorClassifier(err error) bool {
+	if _, ok := eoserr.AsChainError(err); ok {
+		return eoserr.IsTransient(err)
+	}
+	return eoserr.IsTransient(err) || MessageErrorClassifier(retryableNetworkErrorMsgs...)(err)
+}
+
+// MessageErrorClassifier classifies as retryable the errors that contain any of the messages
+func MessageErrorClassifier(msgs ...string) ErrorClassifier {
+	return func(err error) bool {
+		errMsg := err.Error()
+		for _, msg := range msgs {
+			if strings.Contains(errMsg, msg) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// permanentError is never retried whatever the error classifier says, the error it wraps is kept
+// so that it can still be inspected with errors.As and the err package helpers
+type permanentError struct {
+	err error
+}
+
+func (m *permanentError) Error() string {
+	return m.err.Error()
+}
+
+func (m *permanentError) Unwrap() error {
+	return m.err
+}
+
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// AnyErrorClassifier classifies an error as retryable if any of the classifiers does
+func AnyErrorClassifier(classifiers ...ErrorClassifier) ErrorClassifier {
+	return func(err error) bool {
+		for _, classifier := range classifiers {
+			if classifier(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type retryPolicyKey struct{}
+type errorClassifierKey struct{}
+
+// WithRetryPolicy returns a context that overrides the EOS retry policy for the calls made with it
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// WithErrorClassifier returns a context that overrides the EOS error classifier for the calls made with it
+func WithErrorClassifier(ctx context.Context, classifier ErrorClassifier) context.Context {
+	return context.WithValue(ctx, errorClassifierKey{}, classifier)
+}
+
+// WithMaxRetries returns a context that limits the retries of the calls made with it to
+// the specified number, keeping the delays of the retry policy in effect
+func (m *EOS) WithMaxRetries(ctx context.Context, retries uint) context.Context {
+	return WithRetryPolicy(ctx, &maxRetriesPolicy{
+		policy:  m.getRetryPolicy(ctx),
+		retries: retries,
+	})
+}
+
+func (m *EOS) getRetryPolicy(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok && policy != nil {
+		return policy
+	}
+	if m.RetryPolicy != nil {
+		return m.RetryPolicy
+	}
+	return NewConstantRetryPolicy(m.Retries, time.Duration(m.RetrySleep)*time.Second)
+}
+
+func (m *EOS) getErrorClassifier(ctx context.Context) ErrorClassifier {
+	if classifier, ok := ctx.Value(errorClassifierKey{}).(ErrorClassifier); ok && classifier != nil {
+		return classifier
+	}
+	if m.ErrorClassifier != nil {
+		return m.ErrorClassifier
+	}
+	return DefaultErrorClassifier
+}
+
+// withRetries calls fn until it succeeds, it fails with an error that is not retryable,
+// the retry policy gives up or the context is done, secrets are redacted from the returned error
+func (m *EOS) withRetries(ctx context.Context, fn func() error) error {
+	return util.RedactError(m.retry(ctx, fn))
+}
+
+func (m *EOS) retry(ctx context.Context, fn func() error) error {
+	policy := m.getRetryPolicy(ctx)
+	isRetryable := m.getErrorClassifier(ctx)
+	start := time.Now()
+	for attempt := uint(1); ; attempt++ {
+		err := fn()
+		if err == nil || isPermanent(err) || !isRetryable(err) {
+			return err
+		}
+		delay, ok := policy.NextDelay(attempt, time.Since(start))
+		if !ok {
+			return err
+		}
+		if err := sleepCtx(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepCtx waits for the specified duration or until the context is done,
+// whichever happens first, returning the context error in the latter case
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func TestConstantRetryPolicy(t *testing.T) {
+	policy := service.NewConstantRetryPolicy(2, time.Second)
+	delay, ok := policy.NextDelay(1, 0)
+	assert.Assert(t, ok)
+	assert.Equal(t, delay, time.Second)
+	_, ok = policy.NextDelay(2, time.Second)
+	assert.Assert(t, ok)
+	_, ok = policy.NextDelay(3, 2*time.Second)
+	assert.Assert(t, !ok)
+}
+
+func TestExponentialRetryPolicy(t *testing.T) {
+	policy := service.NewExponentialRetryPolicy(5, 100*time.Millisecond, 300*time.Millisecond)
+	policy.Jitter = 0
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, exp := range expected {
+		delay, ok := policy.NextDelay(uint(i+1), 0)
+		assert.Assert(t, ok)
+		assert.Equal(t, delay, exp)
+	}
+	_, ok := policy.NextDelay(6, 0)
+	assert.Assert(t, !ok)
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		delay, _ := policy.NextDelay(1, 0)
+		assert.Assert(t, delay >= 50*time.Millisecond && delay <= 150*time.Millisecond, "delay: %v out of jitter range", delay)
+	}
+}
+
+func TestMaxElapsedTimeRetryPolicy(t *testing.T) {
+	policy := service.NewMaxElapsedTimeRetryPolicy(service.NewConstantRetryPolicy(100, time.Second), 3*time.Second)
+	_, ok := policy.NextDelay(1, time.Second)
+	assert.Assert(t, ok)
+	_, ok = policy.NextDelay(2, 2500*time.Millisecond)
+	assert.Assert(t, !ok)
+}
+
+func TestErrorClassifiers(t *testing.T) {
+	assert.Assert(t, service.DefaultErrorClassifier(errors.New("read: connection reset by peer")))
+	assert.Assert(t, !service.DefaultErrorClassifier(errors.New("assertion failure with message: invalid")))
+	classifier := service.AnyErrorClassifier(service.DefaultErrorClassifier, service.MessageErrorClassifier("database dirty flag set"))
+	assert.Assert(t, classifier(errors.New("database dirty flag set")))
+	assert.Assert(t, classifier(errors.New("context deadline exceeded")))
+	assert.Assert(t, !classifier(errors.New("missing authority")))
+}
+
+func TestDefaultErrorClassifierUsesChainErrorCodes(t *testing.T) {
+	var cpuErr, assertErr eosc.APIError
+	assert.NilError(t, json.Unmarshal([]byte(`{"code": 500, "error": {"code": 3080004, "name": "tx_cpu_usage_exceeded", "what": "Transaction exceeded the current CPU usage limit imposed on the transaction"}}`), &cpuErr))
+	assert.NilError(t, json.Unmarshal([]byte(`{"code": 500, "error": {"code": 3050003, "name": "eosio_assert_message_exception", "what": "eosio_assert_message assertion failure", "details": [{"message": "assertion failure with message: deadline has passed"}]}}`), &assertErr))
+	assert.Assert(t, service.DefaultErrorClassifier(fmt.Errorf("push transaction: %w", cpuErr)))
+	assert.Assert(t, !service.DefaultErrorClassifier(fmt.Errorf("push transaction: %w", assertErr)))
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go/ecc"
+)
+
+// SignerRoute sends the authorizations that match Pattern to Signer, Pattern has the
+// actor@permission format and supports * wildcards e.g. alice@active, alice@*, dao.*@active or *
+type SignerRoute struct {
+	Pattern string
+	Signer  eosc.Signer
+}
+
+func (m *SignerRoute) Matches(level eosc.PermissionLevel) bool {
+	matched, _ := path.Match(m.Pattern, fmt.Sprintf("%v@%v", level.Actor, level.Permission))
+	return matched
+}
+
+// RoutingSigner signs each authorization of a transaction with the signer of the first route that
+// matches it, so that a transaction with multiple actors is signed by each of their signers
+type RoutingSigner struct {
+	routes []*SignerRoute
+	mutex  sync.RWMutex
+}
+
+func NewRoutingSigner() *RoutingSigner {
+	return &RoutingSigner{
+		routes: make([]*SignerRoute, 0),
+	}
+}
+
+// Route adds a route, routes are evaluated in the order they were added
+func (m *RoutingSigner) Route(pattern string, signer eosc.Signer) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid signer route pattern: %v, error: %v", pattern, err)
+	}
+	if signer == nil {
+		return fmt.Errorf("failed adding signer route: %v, signer is nil", pattern)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.routes = append(m.routes, &SignerRoute{
+		Pattern: pattern,
+		Signer:  signer,
+	})
+	return nil
+}
+
+// SignerFor returns the signer the authorization is routed to
+func (m *RoutingSigner) SignerFor(level eosc.PermissionLevel) (eosc.Signer, bool) {
+	route := m.routeFor(level)
+	if route == nil {
+		return nil, false
+	}
+	return route.Signer, true
+}
+
+func (m *RoutingSigner) routeFor(level eosc.PermissionLevel) *SignerRoute {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, route := range m.routes {
+		if route.Matches(level) {
+			return route
+		}
+	}
+	return nil
+}
+
+// AvailableKeys implements eosc.Signer, returns the keys of all the signers
+func (m *RoutingSigner) AvailableKeys(ctx context.Context) ([]ecc.PublicKey, error) {
+	keys := make([]ecc.PublicKey, 0)
+	for _, route := range m.routesCopy() {
+		signerKeys, err := route.Signer.AvailableKeys(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting available keys of signer of route: %v, error: %w", route.Pattern, err)
+		}
+		keys = append(keys, keysDifference(signerKeys, keys)...)
+	}
+	return keys, nil
+}
+
+// Sign implements eosc.Signer, each signer signs with the required keys it holds, it fails with an
+// eoserr.MissingSignerError if any authorization is not routed or some required key is not held by
+// any of the signers the authorizations are routed to
+func (m *RoutingSigner) Sign(ctx context.Context, tx *eosc.SignedTransaction, chainID []byte, requiredKeys ...ecc.PublicKey) (*eosc.SignedTransaction, error) {
+	// authorizations are grouped by route, signers may not be comparable so they can not be map keys
+	routes := make([]*SignerRoute, 0)
+	authsByRoute := make(map[*SignerRoute][]string)
+	missing := make([]string, 0)
+	seen := make(map[eosc.PermissionLevel]bool)
+	actions := make([]*eosc.Action, 0, len(tx.ContextFreeActions)+len(tx.Actions))
+	actions = append(append(actions, tx.ContextFreeActions...), tx.Actions...)
+	for _, action := range actions {
+		for _, auth := range action.Authorization {
+			if seen[auth] {
+				continue
+			}
+			seen[auth] = true
+			authName := fmt.Sprintf("%v@%v", auth.Actor, auth.Permission)
+			route := m.routeFor(auth)
+			if route == nil {
+				missing = append(missing, authName)
+				continue
+			}
+			if _, ok := authsByRoute[route]; !ok {
+				routes = append(routes, route)
+			}
+			authsByRoute[route] = append(authsByRoute[route], authName)
+		}
+	}
+	signatures := make([]ecc.Signature, 0)
+	signedKeys := make([]ecc.PublicKey, 0)
+	// a required key may satisfy the authorization of another actor, so the authorizations of a signer
+	// that holds none of the required keys are only reported if some required key is not held by any signer
+	unsigned := make([]string, 0)
+	for _, route := range routes {
+		availableKeys, err := route.Signer.AvailableKeys(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting available keys of signer for: %v, error: %w", authsByRoute[route], err)
+		}
+		heldKeys := keysIntersection(requiredKeys, availableKeys)
+		if len(heldKeys) == 0 {
+			unsigned = append(unsigned, authsByRoute[route]...)
+			continue
+		}
+		// keys held by more than one signer are used only once
+		keys := keysDifference(heldKeys, signedKeys)
+		if len(keys) == 0 {
+			continue
+		}
+		signerTx := *tx
+		signerTx.Signatures = nil
+		signedTx, err := route.Signer.Sign(ctx, &signerTx, chainID, keys...)
+		if err != nil {
+			return nil, fmt.Errorf("failed signing for: %v, error: %w", authsByRoute[route], err)
+		}
+		signatures = append(signatures, signedTx.Signatures...)
+		signedKeys = append(signedKeys, keys...)
+	}
+	if missingKeys := keysDifference(requiredKeys, signedKeys); len(missingKeys) > 0 {
+		missing = append(missing, unsigned...)
+	}
+	if len(missing) > 0 {
+		return nil, &eoserr.MissingSignerError{Authorizations: missing}
+	}
+	tx.Signatures = append(tx.Signatures, signatures...)
+	return tx, nil
+}
+
+// ImportPrivateKey implements eosc.Signer, keys have to be imported into the routed signers
+func (m *RoutingSigner) ImportPrivateKey(ctx context.Context, wifPrivKey string) error {
+	return fmt.Errorf("importing keys is not supported by the routing signer, import them into the routed signers")
+}
+
+func (m *RoutingSigner) routesCopy() []*SignerRoute {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]*SignerRoute(nil), m.routes...)
+}
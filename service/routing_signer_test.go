@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"gotest.tools/assert"
+)
+
+func newTestKeyBag(t *testing.T) (*eosc.KeyBag, ecc.PublicKey) {
+	key, err := ecc.NewRandomPrivateKey()
+	assert.NilError(t, err)
+	keyBag := eosc.NewKeyBag()
+	assert.NilError(t, keyBag.Append(key))
+	return keyBag, key.PublicKey()
+}
+
+func newTestAuthTrx(auths ...string) *eosc.SignedTransaction {
+	tx := newTestSignedTrx()
+	tx.Actions[0].Authorization = make([]eosc.PermissionLevel, 0, len(auths))
+	for _, auth := range auths {
+		level, _ := eosc.NewPermissionLevel(auth)
+		tx.Actions[0].Authorization = append(tx.Actions[0].Authorization, level)
+	}
+	return tx
+}
+
+func TestRoutingSigner(t *testing.T) {
+	aliceBag, aliceKey := newTestKeyBag(t)
+	bobBag, bobKey := newTestKeyBag(t)
+	signer := service.NewRoutingSigner()
+	assert.NilError(t, signer.Route("alice@*", aliceBag))
+	assert.NilError(t, signer.Route("bob@active", bobBag))
+	assert.ErrorContains(t, signer.Route("[", aliceBag), "invalid signer route pattern")
+
+	keys, err := signer.AvailableKeys(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(keys), 2)
+
+	tx := newTestAuthTrx("alice@owner", "bob@active")
+	signedTx, err := signer.Sign(context.Background(), tx, make([]byte, 32), aliceKey, bobKey)
+	assert.NilError(t, err)
+	assert.Equal(t, len(signedTx.Signatures), 2)
+
+	tx = newTestAuthTrx("alice@active", "bob@owner", "carol@active")
+	_, err = signer.Sign(context.Background(), tx, make([]byte, 32), aliceKey)
+	var missingErr *eoserr.MissingSignerError
+	assert.Assert(t, errors.As(err, &missingErr))
+	assert.DeepEqual(t, missingErr.Authorizations, []string{"bob@owner", "carol@active"})
+}
+
+// valueSigner is not comparable, routes must not use signers as map keys
+type valueSigner struct {
+	keyBags []*eosc.KeyBag
+}
+
+func (m valueSigner) AvailableKeys(ctx context.Context) ([]ecc.PublicKey, error) {
+	return m.keyBags[0].AvailableKeys(ctx)
+}
+
+func (m valueSigner) Sign(ctx context.Context, tx *eosc.SignedTransaction, chainID []byte, requiredKeys ...ecc.PublicKey) (*eosc.SignedTransaction, error) {
+	return m.keyBags[0].Sign(ctx, tx, chainID, requiredKeys...)
+}
+
+func (m valueSigner) ImportPrivateKey(ctx context.Context, wifPrivKey string) error {
+	return m.keyBags[0].ImportPrivateKey(ctx, wifPrivKey)
+}
+
+func TestRoutingSignerKeySharedByActors(t *testing.T) {
+	aliceBag, aliceKey := newTestKeyBag(t)
+	bobBag, _ := newTestKeyBag(t)
+	signer := service.NewRoutingSigner()
+	assert.NilError(t, signer.Route("alice@*", valueSigner{keyBags: []*eosc.KeyBag{aliceBag}}))
+	assert.NilError(t, signer.Route("bob@*", valueSigner{keyBags: []*eosc.KeyBag{bobBag}}))
+
+	// the alice key also satisfies bob@active, so it is the only required key
+	tx := newTestAuthTrx("alice@active", "bob@active")
+	signedTx, err := signer.Sign(context.Background(), tx, make([]byte, 32), aliceKey)
+	assert.NilError(t, err)
+	assert.Equal(t, len(signedTx.Signatures), 1)
+
+	_, carolKey := newTestKeyBag(t)
+	tx = newTestAuthTrx("alice@active", "bob@active")
+	_, err = signer.Sign(context.Background(), tx, make([]byte, 32), aliceKey, carolKey)
+	var missingErr *eoserr.MissingSignerError
+	assert.Assert(t, errors.As(err, &missingErr))
+	assert.DeepEqual(t, missingErr.Authorizations, []string{"bob@active"})
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+	"github.com/sebastianmontero/eos-go-toolbox/keystore"
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"github.com/sebastianmontero/eos-go/ecc"
+)
+
+const defaultSignerTimeout = 10 * time.Second
+
+// keosd error codes, see plugins/wallet_plugin
+const (
+	walletLockedExceptionCode   = 3120003
+	walletUnlockedExceptionCode = 3120007
+)
+
+// KeosdSigner signs transactions using the wallet API of a keosd instance, so that the
+// private keys are held by keosd and never loaded into the process
+type KeosdSigner struct {
+	URL        string
+	WalletName string
+	// password is used to unlock the wallet when keosd reports it is locked, it can be empty
+	password   string
+	HTTPClient *http.Client
+}
+
+func NewKeosdSigner(url, walletName, password string) *KeosdSigner {
+	if walletName == "" {
+		walletName = "default"
+	}
+	return &KeosdSigner{
+		URL:        strings.TrimSuffix(url, "/"),
+		WalletName: walletName,
+		password:   password,
+		HTTPClient: &http.Client{Timeout: defaultSignerTimeout},
+	}
+}
+
+func (m *KeosdSigner) AvailableKeys(ctx context.Context) ([]ecc.PublicKey, error) {
+	var keys []ecc.PublicKey
+	err := m.callUnlocked(ctx, "get_public_keys", nil, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting keosd public keys, error: %w", err)
+	}
+	return keys, nil
+}
+
+func (m *KeosdSigner) Sign(ctx context.Context, tx *eosc.SignedTransaction, chainID []byte, requiredKeys ...ecc.PublicKey) (*eosc.SignedTransaction, error) {
+	var signedTx eosc.SignedTransaction
+	err := m.callUnlocked(ctx, "sign_transaction", []interface{}{tx, requiredKeys, hex.EncodeToString(chainID)}, &signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed signing transaction with keosd, error: %w", err)
+	}
+	tx.Signatures = signedTx.Signatures
+	return tx, nil
+}
+
+func (m *KeosdSigner) ImportPrivateKey(ctx context.Context, wifPrivKey string) error {
+	err := m.callUnlocked(ctx, "import_key", []string{m.WalletName, wifPrivKey}, nil)
+	if err != nil {
+		return fmt.Errorf("failed importing key into keosd wallet: %v, error: %w", m.WalletName, util.RedactError(err))
+	}
+	return nil
+}
+
+// Unlock unlocks the wallet, it does not fail if the wallet is already unlocked
+func (m *KeosdSigner) Unlock(ctx context.Context) error {
+	err := postJSON(ctx, m.HTTPClient, m.URL+"/v1/wallet/unlock", nil, []string{m.WalletName, m.password}, nil)
+	if err != nil && !hasChainErrorCode(err, walletUnlockedExceptionCode) {
+		return fmt.Errorf("failed unlocking keosd wallet: %v, error: %w", m.WalletName, util.RedactError(err))
+	}
+	return nil
+}
+
+// callUnlocked calls the wallet endpoint, unlocking the wallet and trying again if it is locked
+func (m *KeosdSigner) callUnlocked(ctx context.Context, endpoint string, body, out interface{}) error {
+	url := m.URL + "/v1/wallet/" + endpoint
+	err := postJSON(ctx, m.HTTPClient, url, nil, body, out)
+	if err == nil || m.password == "" || !hasChainErrorCode(err, walletLockedExceptionCode) {
+		return err
+	}
+	if err := m.Unlock(ctx); err != nil {
+		return err
+	}
+	return postJSON(ctx, m.HTTPClient, url, nil, body, out)
+}
+
+// UnlockKeystore opens the keystore file with the passphrase and uses it as the signer
+func (m *EOS) UnlockKeystore(path, passphrase string) (*keystore.Keystore, error) {
+	ks, err := keystore.Open(path, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed unlocking keystore, error: %w", err)
+	}
+	m.API.SetSigner(ks)
+	return ks, nil
+}
+
+// RemoteSigner signs transactions using a remote signing service, the service must implement:
+//
+// POST {URL}/v1/keys returns {"keys": ["EOS..."]}
+//
+// POST {URL}/v1/sign with {"chain_id": "hex", "transaction": {...}, "required_keys": ["EOS..."]}
+// returns {"signatures": ["SIG_K1_..."]}
+type RemoteSigner struct {
+	URL string
+	// Headers are added to every request, e.g. for authentication
+	Headers    map[string]string
+	HTTPClient *http.Client
+}
+
+type RemoteSignRequest struct {
+	ChainID      eosc.HexBytes           `json:"chain_id"`
+	Transaction  *eosc.SignedTransaction `json:"transaction"`
+	RequiredKeys []ecc.PublicKey         `json:"required_keys"`
+}
+
+type RemoteSignResponse struct {
+	Signatures []ecc.Signature `json:"signatures"`
+}
+
+type RemoteKeysResponse struct {
+	Keys []ecc.PublicKey `json:"keys"`
+}
+
+func NewRemoteSigner(url string, headers map[string]string) *RemoteSigner {
+	return &RemoteSigner{
+		URL:        strings.TrimSuffix(url, "/"),
+		Headers:    headers,
+		HTTPClient: &http.Client{Timeout: defaultSignerTimeout},
+	}
+}
+
+func (m *RemoteSigner) AvailableKeys(ctx context.Context) ([]ecc.PublicKey, error) {
+	var resp RemoteKeysResponse
+	err := postJSON(ctx, m.HTTPClient, m.URL+"/v1/keys", m.Headers, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting remote signer keys, error: %w", err)
+	}
+	return resp.Keys, nil
+}
+
+func (m *RemoteSigner) Sign(ctx context.Context, tx *eosc.SignedTransaction, chainID []byte, requiredKeys ...ecc.PublicKey) (*eosc.SignedTransaction, error) {
+	var resp RemoteSignResponse
+	err := postJSON(ctx, m.HTTPClient, m.URL+"/v1/sign", m.Headers, &RemoteSignRequest{
+		ChainID:      chainID,
+		Transaction:  tx,
+		RequiredKeys: requiredKeys,
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed signing transaction with remote signer, error: %w", err)
+	}
+	tx.Signatures = append(tx.Signatures, resp.Signatures...)
+	return tx, nil
+}
+
+func (m *RemoteSigner) ImportPrivateKey(ctx context.Context, wifPrivKey string) error {
+	return fmt.Errorf("importing keys is not supported by the remote signer")
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed marshalling request, error: %v", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed reading response, error: %v", err)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr eosc.APIError
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.ErrorStruct.Code != 0 {
+			return eoserr.NewChainError(&apiErr)
+		}
+		return fmt.Errorf("request failed with status code: %v", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	err = json.Unmarshal(respBody, out)
+	if err != nil {
+		return fmt.Errorf("failed unmarshalling response, error: %v", err)
+	}
+	return nil
+}
+
+func hasChainErrorCode(err error, code int) bool {
+	chainErr, ok := eoserr.AsChainError(err)
+	return ok && chainErr.Code == code
+}
@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"github.com/sebastianmontero/eos-go/ecc"
+	"gotest.tools/assert"
+)
+
+const eosioPublicKey = "EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV"
+
+func newTestSignedTrx() *eosc.SignedTransaction {
+	action := newAccountActions("signera")[0]
+	tx := eosc.NewTransaction([]*eosc.Action{action}, &eosc.TxOptions{HeadBlockID: make(eosc.Checksum256, 32)})
+	return eosc.NewSignedTransaction(tx)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	keyBag := eosc.NewKeyBag()
+	assert.NilError(t, keyBag.Add(service.EOSIOKey))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/keys":
+			keys, _ := keyBag.AvailableKeys(r.Context())
+			json.NewEncoder(w).Encode(&service.RemoteKeysResponse{Keys: keys})
+		case "/v1/sign":
+			var req service.RemoteSignRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			signedTx, err := keyBag.Sign(r.Context(), req.Transaction, req.ChainID, req.RequiredKeys...)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(&service.RemoteSignResponse{Signatures: signedTx.Signatures})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	signer := service.NewRemoteSigner(server.URL, map[string]string{"Authorization": "Bearer secret"})
+	keys, err := signer.AvailableKeys(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(keys), 1)
+	signedTx, err := signer.Sign(context.Background(), newTestSignedTrx(), make([]byte, 32), keys...)
+	assert.NilError(t, err)
+	assert.Equal(t, len(signedTx.Signatures), 1)
+
+	signer = service.NewRemoteSigner(server.URL, nil)
+	_, err = signer.AvailableKeys(context.Background())
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestKeosdSignerUnlocksWallet(t *testing.T) {
+	locked := true
+	unlocks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/wallet/unlock":
+			var params []string
+			json.NewDecoder(r.Body).Decode(&params)
+			if len(params) != 2 || params[0] != "ops" || params[1] != "PW5secret" {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"code":500,"message":"Internal Service Error","error":{"code":3120005,"name":"wallet_invalid_password_exception","what":"Invalid wallet password","details":[]}}`))
+				return
+			}
+			unlocks++
+			locked = false
+			w.Write([]byte(`{}`))
+		case "/v1/wallet/get_public_keys":
+			if locked {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"code":500,"message":"Internal Service Error","error":{"code":3120003,"name":"wallet_locked_exception","what":"Locked wallet","details":[]}}`))
+				return
+			}
+			json.NewEncoder(w).Encode([]string{eosioPublicKey})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	signer := service.NewKeosdSigner(server.URL, "ops", "PW5secret")
+	keys, err := signer.AvailableKeys(context.Background())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys, []ecc.PublicKey{ecc.MustNewPublicKey(eosioPublicKey)})
+	assert.Equal(t, unlocks, 1)
+
+	locked = true
+	signer = service.NewKeosdSigner(server.URL, "ops", "wrong")
+	_, err = signer.AvailableKeys(context.Background())
+	assert.ErrorContains(t, err, "Invalid wallet password")
+	assert.Assert(t, !strings.Contains(err.Error(), "wrong"))
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	eoserr "github.com/sebastianmontero/eos-go-toolbox/err"
+)
+
+const defaultTaposCacheTTL = time.Minute
+
+type TaposCacheStats struct {
+	// SavedRoundTrips is the number of transactions built without calling get_info
+	SavedRoundTrips uint64 `json:"saved_round_trips"`
+	Refreshes       uint64 `json:"refreshes"`
+	Invalidations   uint64 `json:"invalidations"`
+}
+
+func (m *TaposCacheStats) String() string {
+	return fmt.Sprintf("SavedRoundTrips: %v, Refreshes: %v, Invalidations: %v", m.SavedRoundTrips, m.Refreshes, m.Invalidations)
+}
+
+// TaposCache caches the chain id and the reference block used to fill the TAPOS fields of
+// transactions, so that a get_info call is not required for every transaction, the reference
+// block has to be one of the last 65536 blocks so the TTL should be kept well below 9 hours
+type TaposCache struct {
+	ttl        time.Duration
+	fetchInfo  func(ctx context.Context) (*eosc.InfoResp, error)
+	mutex      sync.Mutex
+	chainID    eosc.Checksum256
+	refBlockID eosc.Checksum256
+	fetchedAt  time.Time
+	stats      TaposCacheStats
+	inFlight   *taposRefresh
+}
+
+// taposRefresh is a get_info call in progress, callers that need the reference block while
+// it is being fetched wait for it instead of making their own call
+type taposRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+func NewTaposCache(fetchInfo func(ctx context.Context) (*eosc.InfoResp, error), ttl time.Duration) *TaposCache {
+	if ttl <= 0 {
+		ttl = defaultTaposCacheTTL
+	}
+	return &TaposCache{
+		ttl:       ttl,
+		fetchInfo: fetchInfo,
+	}
+}
+
+// TxOptions returns transaction options with the cached chain id and reference block,
+// they are fetched from the chain if the cache is empty or has expired
+func (m *TaposCache) TxOptions(ctx context.Context) (*eosc.TxOptions, error) {
+	m.mutex.Lock()
+	if m.refBlockID != nil && time.Since(m.fetchedAt) <= m.ttl {
+		m.stats.SavedRoundTrips++
+		defer m.mutex.Unlock()
+		return m.txOptions(), nil
+	}
+	m.mutex.Unlock()
+	for {
+		err := m.Refresh(ctx)
+		if err != nil {
+			return nil, err
+		}
+		m.mutex.Lock()
+		// the reference block could have been invalidated right after being refreshed
+		if m.refBlockID != nil {
+			defer m.mutex.Unlock()
+			return m.txOptions(), nil
+		}
+		m.mutex.Unlock()
+	}
+}
+
+// txOptions must be called with the lock held
+func (m *TaposCache) txOptions() *eosc.TxOptions {
+	return &eosc.TxOptions{
+		ChainID:     m.chainID,
+		HeadBlockID: m.refBlockID,
+	}
+}
+
+// Refresh fetches the chain id and reference block from the chain, if a refresh is already
+// in progress it waits for it instead of fetching them again
+func (m *TaposCache) Refresh(ctx context.Context) error {
+	for {
+		m.mutex.Lock()
+		refresh := m.inFlight
+		if refresh == nil {
+			refresh = &taposRefresh{done: make(chan struct{})}
+			m.inFlight = refresh
+			m.mutex.Unlock()
+			m.refresh(ctx, refresh)
+			return refresh.err
+		}
+		m.mutex.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-refresh.done:
+		}
+		// the refresh was made with the context of another caller, if it was canceled try again
+		if refresh.err == nil || !isContextError(refresh.err) {
+			return refresh.err
+		}
+	}
+}
+
+// refresh calls get_info without holding the lock, so that transactions using a valid
+// reference block are not blocked by it
+func (m *TaposCache) refresh(ctx context.Context, refresh *taposRefresh) {
+	info, err := m.fetchInfo(ctx)
+	m.mutex.Lock()
+	defer close(refresh.done)
+	defer m.mutex.Unlock()
+	m.inFlight = nil
+	if err != nil {
+		refresh.err = fmt.Errorf("failed refreshing tapos reference block, error: %w", err)
+		return
+	}
+	m.chainID = info.ChainID
+	// the last irreversible block is used as reference block, as it can not be forked out
+	m.refBlockID = info.LastIrreversibleBlockID
+	m.fetchedAt = time.Now()
+	m.stats.Refreshes++
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Invalidate clears the cached reference block, so that it is fetched again on the next transaction
+func (m *TaposCache) Invalidate() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.refBlockID = nil
+	m.stats.Invalidations++
+}
+
+func (m *TaposCache) Stats() *TaposCacheStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stats := m.stats
+	return &stats
+}
+
+// Start refreshes the reference block in the background every half TTL until the context is done,
+// so that transactions never wait for get_info
+func (m *TaposCache) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.ttl / 2)
+		defer ticker.Stop()
+		for {
+			// errors are ignored, the cache expires and is refreshed on demand if refreshing keeps failing
+			m.Refresh(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// EnableTaposCache makes transactions use a cached reference block that is refreshed every ttl
+func (m *EOS) EnableTaposCache(ttl time.Duration) *TaposCache {
+	m.TaposCache = NewTaposCache(m.GetInfoCtx, ttl)
+	return m.TaposCache
+}
+
+// txOptions returns the options to build a transaction, from the TAPOS cache if enabled
+func (m *EOS) txOptions(ctx context.Context) (*eosc.TxOptions, error) {
+	if m.TaposCache != nil {
+		return m.TaposCache.TxOptions(ctx)
+	}
+	txOpts := &eosc.TxOptions{}
+	if err := m.withRetries(ctx, func() error { return txOpts.FillFromChain(ctx, m.API) }); err != nil {
+		return nil, err
+	}
+	return txOpts, nil
+}
+
+func (m *EOS) signPushActions(ctx context.Context, actions []*eosc.Action) (*eosc.PushTransactionFullResp, error) {
+	txOpts, err := m.txOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.signPushTrx(ctx, actions, txOpts)
+	if m.TaposCache != nil && (eoserr.IsInvalidRefBlock(err) || eoserr.IsExpired(err)) {
+		// the cached reference block is stale, fetch a new one and try again
+		m.TaposCache.Invalidate()
+		txOpts, err = m.TaposCache.TxOptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return m.signPushTrx(ctx, actions, txOpts)
+	}
+	return resp, err
+}
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	eosc "github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/service"
+	"gotest.tools/assert"
+)
+
+func TestTaposCache(t *testing.T) {
+	calls := 0
+	cache := service.NewTaposCache(func(ctx context.Context) (*eosc.InfoResp, error) {
+		calls++
+		return &eosc.InfoResp{
+			ChainID:                 eosc.Checksum256{1},
+			LastIrreversibleBlockID: eosc.Checksum256{byte(calls)},
+		}, nil
+	}, time.Hour)
+
+	for i := 0; i < 5; i++ {
+		txOpts, err := cache.TxOptions(context.Background())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, txOpts.ChainID, eosc.Checksum256{1})
+		assert.DeepEqual(t, txOpts.HeadBlockID, eosc.Checksum256{1})
+	}
+	assert.Equal(t, calls, 1)
+	stats := cache.Stats()
+	assert.Equal(t, stats.SavedRoundTrips, uint64(4))
+	assert.Equal(t, stats.Refreshes, uint64(1))
+
+	cache.Invalidate()
+	txOpts, err := cache.TxOptions(context.Background())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, txOpts.HeadBlockID, eosc.Checksum256{2})
+	assert.Equal(t, cache.Stats().Invalidations, uint64(1))
+}
+
+func TestTaposCacheExpires(t *testing.T) {
+	calls := 0
+	cache := service.NewTaposCache(func(ctx context.Context) (*eosc.InfoResp, error) {
+		calls++
+		if calls > 1 {
+			return nil, errors.New("node down")
+		}
+		return &eosc.InfoResp{LastIrreversibleBlockID: eosc.Checksum256{1}}, nil
+	}, time.Millisecond)
+
+	_, err := cache.TxOptions(context.Background())
+	assert.NilError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = cache.TxOptions(context.Background())
+	assert.ErrorContains(t, err, "node down")
+	assert.Equal(t, calls, 2)
+}
+
+func TestTaposCacheConcurrentRefreshFetchesOnce(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	cache := service.NewTaposCache(func(ctx context.Context) (*eosc.InfoResp, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &eosc.InfoResp{LastIrreversibleBlockID: eosc.Checksum256{1}}, nil
+	}, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			txOpts, err := cache.TxOptions(context.Background())
+			assert.NilError(t, err)
+			assert.DeepEqual(t, txOpts.HeadBlockID, eosc.Checksum256{1})
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(1))
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+// WriteContract writes to dir a minimal contract with an empty apply function and an ABI with
+// the specified actions, each taking a name parameter, different versions produce different code
+func WriteContract(t *testing.T, dir string, version int, actions ...string) (wasmFile, abiFile string) {
+	assert.Assert(t, version >= 0 && version < 100, "version: %v out of range", version)
+	wasm := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		// type section: func(i64, i64, i64)
+		0x01, 0x07, 0x01, 0x60, 0x03, 0x7e, 0x7e, 0x7e, 0x00,
+		// function section
+		0x03, 0x02, 0x01, 0x00,
+		// export section: apply
+		0x07, 0x09, 0x01, 0x05, 'a', 'p', 'p', 'l', 'y', 0x00, 0x00,
+	}
+	// code section, the body has as many nops as the version
+	body := []byte{0x00}
+	for i := 0; i < version; i++ {
+		body = append(body, 0x01)
+	}
+	body = append(body, 0x0b)
+	wasm = append(wasm, 0x0a, byte(len(body)+2), 0x01, byte(len(body)))
+	wasm = append(wasm, body...)
+
+	structs := make([]map[string]interface{}, 0, len(actions))
+	abiActions := make([]map[string]interface{}, 0, len(actions))
+	for _, action := range actions {
+		structs = append(structs, map[string]interface{}{
+			"name":   action,
+			"base":   "",
+			"fields": []map[string]string{{"name": "user", "type": "name"}},
+		})
+		abiActions = append(abiActions, map[string]interface{}{
+			"name":               action,
+			"type":               action,
+			"ricardian_contract": "",
+		})
+	}
+	abi, err := json.Marshal(map[string]interface{}{
+		"version":           "eosio::abi/1.1",
+		"types":             []interface{}{},
+		"structs":           structs,
+		"actions":           abiActions,
+		"tables":            []interface{}{},
+		"ricardian_clauses": []interface{}{},
+		"variants":          []interface{}{},
+	})
+	assert.NilError(t, err)
+
+	name := fmt.Sprintf("contract%v_%v", version, strings.Join(actions, "_"))
+	wasmFile = filepath.Join(dir, name+".wasm")
+	abiFile = filepath.Join(dir, name+".abi")
+	assert.NilError(t, ioutil.WriteFile(wasmFile, wasm, 0644))
+	assert.NilError(t, ioutil.WriteFile(abiFile, abi, 0644))
+	return wasmFile, abiFile
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"regexp"
+)
+
+const Redacted = "[REDACTED]"
+
+var secretPatterns = []*regexp.Regexp{
+	// legacy WIF private keys
+	regexp.MustCompile(`\b5[HJK][1-9A-HJ-NP-Za-km-z]{49}\b`),
+	// PVT_K1_, PVT_R1_ private keys
+	regexp.MustCompile(`\bPVT_[A-Z0-9]{2}_[1-9A-HJ-NP-Za-km-z]{40,}\b`),
+	// keosd wallet passwords
+	regexp.MustCompile(`\bPW5[1-9A-HJ-NP-Za-km-z]{40,}\b`),
+}
+
+// Redact replaces the private keys and wallet passwords found in s
+func Redact(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// RedactedError is an error whose message has been redacted, the original error is still
+// available through Unwrap so that it can be inspected with errors.Is and errors.As
+type RedactedError struct {
+	msg string
+	err error
+}
+
+func (m *RedactedError) Error() string {
+	return m.msg
+}
+
+func (m *RedactedError) Unwrap() error {
+	return m.err
+}
+
+// RedactError returns err unchanged if its message does not contain secrets, otherwise
+// returns a RedactedError wrapping it
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	redacted := Redact(msg)
+	if redacted == msg {
+		return err
+	}
+	return &RedactedError{
+		msg: redacted,
+		err: err,
+	}
+}
@@ -0,0 +1,33 @@
+package util_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sebastianmontero/eos-go-toolbox/util"
+	"gotest.tools/assert"
+)
+
+func TestRedact(t *testing.T) {
+	wif := "5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3"
+	pvt := "PVT_K1_2bfGi9rYsXQSXXTvJbDAPhHLQUojjaNLomdm3cEJ1XTzMqUt3V"
+	password := "PW5KFWqXoJ8pJWJ6p6Pnr3w7t2yf8ykD56DnRbS8PDmyNvmFuM6Zk"
+	publicKey := "EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV"
+
+	msg := fmt.Sprintf("keys: %v, %v, password: %v, public key: %v", wif, pvt, password, publicKey)
+	assert.Equal(t, util.Redact(msg), fmt.Sprintf("keys: %v, %v, password: %v, public key: %v", util.Redacted, util.Redacted, util.Redacted, publicKey))
+}
+
+func TestRedactError(t *testing.T) {
+	assert.NilError(t, util.RedactError(nil))
+
+	original := errors.New("not a secret")
+	assert.Equal(t, util.RedactError(original), original)
+
+	original = fmt.Errorf("invalid key: 5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	err := util.RedactError(original)
+	assert.Assert(t, !strings.Contains(err.Error(), "5KQwr"))
+	assert.Assert(t, errors.Is(err, original))
+}
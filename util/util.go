@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/sebastianmontero/eos-go"
+	"github.com/sebastianmontero/eos-go/ecc"
 )
 
 const (
@@ -280,6 +281,26 @@ func ToPermissionLevel(permissionLevel interface{}) (eos.PermissionLevel, error)
 	}
 }
 
+func ToPublicKey(value interface{}) (ecc.PublicKey, error) {
+	switch v := value.(type) {
+	case string:
+		key, err := ecc.NewPublicKey(v)
+		if err != nil {
+			return ecc.PublicKey{}, fmt.Errorf("failed to parse value: %v to PublicKey, error: %v", v, err)
+		}
+		return key, nil
+	case ecc.PublicKey:
+		return v, nil
+	case *ecc.PublicKey:
+		if v == nil {
+			return ecc.PublicKey{}, fmt.Errorf("failed to convert nil to PublicKey")
+		}
+		return *v, nil
+	default:
+		return ecc.PublicKey{}, fmt.Errorf("failed to convert: %v of type: %T to PublicKey", value, value)
+	}
+}
+
 func StringWithCharset(length int, charset string) string {
 	b := make([]byte, length)
 	for i := range b {